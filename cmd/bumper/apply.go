@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+func applyCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	remote := fs.String(
+		"remote",
+		"origin",
+		"Remote to push the bump to.",
+	)
+	pr := fs.Bool(
+		"pr",
+		false,
+		"Open or update a pull request for the bump instead of pushing it.",
+	)
+	var prBranch string
+	registerPRBranch(fs, &prBranch)
+
+	return func() int {
+		if g.refuseOverrides("apply") {
+			return exitUsage
+		}
+
+		e := g.env()
+
+		r, err := e.newBumper(e.commitRange(g.commitRange), logger.NewLogger()).FindBump()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if r.BumpSHA == "" {
+			log.Print("nothing to bump")
+			return exitCode(r)
+		}
+
+		target := e.targetBranch(g, r.CommitRange)
+		if *pr {
+			err = openPullRequest(e.ghc, g.githubRepo, r, prBranch, target)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return exitCode(r)
+		}
+
+		err = e.gc.Push(*remote, r.BumpSHA, target)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("pushed %s to %s/%s", r.BumpSHA, *remote, target)
+
+		return exitCode(r)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+func auditCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	since := fs.String(
+		"since",
+		"",
+		"Start of the commits on -target-branch to audit. Defaults to the latest tag.",
+	)
+	output := fs.String(
+		"output",
+		"table",
+		"Output format: table or json.",
+	)
+
+	return func() int {
+		if g.refuseOverrides("audit") {
+			return exitUsage
+		}
+
+		e := g.env()
+		target := e.targetBranch(g, "")
+
+		start := *since
+		if start == "" {
+			var err error
+			start, err = e.gc.LatestTag(target)
+			if err != nil {
+				log.Fatalf("failed to find the latest tag, use -since: %s", err)
+			}
+		}
+
+		b := bumper.New(start+".."+target, logger.NewLogger(),
+			bumper.WithGitClient(e.gc),
+			bumper.WithTrackerClient(e.stc),
+		)
+		r, err := b.Bump()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		n, err := writeAudit(os.Stdout, r, *output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if n > 0 {
+			return exitUnaccepted
+		}
+		return 0
+	}
+}
+
+type auditReport struct {
+	CommitRange string        `json:"commit_range"`
+	Unaccepted  []auditCommit `json:"unaccepted"`
+}
+
+type auditCommit struct {
+	SHA       string `json:"sha"`
+	Subject   string `json:"subject"`
+	StoryID   int    `json:"story_id"`
+	StoryName string `json:"story_name"`
+}
+
+// writeAudit writes the commits of the range whose stories are not accepted
+// in the given output format. It returns how many there are.
+func writeAudit(w io.Writer, r bumper.Result, output string) (int, error) {
+	report := auditReport{
+		CommitRange: r.CommitRange,
+		Unaccepted:  []auditCommit{},
+	}
+	for _, c := range r.Unaccepted() {
+		report.Unaccepted = append(report.Unaccepted, auditCommit{
+			SHA:       c.Hash,
+			Subject:   strings.TrimSpace(c.Subject),
+			StoryID:   c.StoryID,
+			StoryName: c.StoryName,
+		})
+	}
+
+	switch output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return len(report.Unaccepted), enc.Encode(report)
+	case "table":
+	default:
+		return 0, fmt.Errorf("unknown output format %q", output)
+	}
+
+	if len(report.Unaccepted) == 0 {
+		fmt.Fprintf(w, "All stories in %s are accepted.\n", r.CommitRange)
+		return 0, nil
+	}
+
+	fmt.Fprintf(w, "Commits in %s whose stories are not accepted:\n\n", r.CommitRange)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SHA\tSTORY\tSUBJECT\tSTORY NAME")
+	for _, c := range report.Unaccepted {
+		fmt.Fprintf(tw, "%.8s\t%d\t%s\t%s\n", c.SHA, c.StoryID, c.Subject, c.StoryName)
+	}
+	return len(report.Unaccepted), tw.Flush()
+}
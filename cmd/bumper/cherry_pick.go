@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/cherrypick"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+func cherryPickCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	branch := fs.String(
+		"branch",
+		cherrypick.DefaultBranch,
+		"Branch the verified cherry-pick plan is written to.",
+	)
+
+	return func() int {
+		e := g.env()
+
+		r, err := e.newBumper(e.commitRange(g.commitRange), logger.NewLogger()).Bump()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		p := cherrypick.New(e.gc, cherrypick.WithBranch(*branch))
+		err = planCherryPicks(os.Stdout, p, r)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return 0
+	}
+}
+
+// planCherryPicks proposes and verifies cherry-picks past the commits
+// blocking the bump and writes the plan.
+func planCherryPicks(w io.Writer, p cherrypick.Planner, r bumper.Result) error {
+	plan, err := p.Plan(r)
+	if err != nil {
+		return err
+	}
+
+	if plan.Blocker == nil {
+		fmt.Fprintf(w, "Nothing is blocking the bump of %s.\n", r.CommitRange)
+		return nil
+	}
+
+	fmt.Fprintf(w, "Blocked by %s %s\n", plan.Blocker.ShortSHA(), strings.TrimSpace(plan.Blocker.FormatSubject(60)))
+
+	if len(plan.Picks) == 0 {
+		fmt.Fprintln(w, "No accepted commits can be cherry-picked past it.")
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nCherry-pick onto %.8s:\n", plan.Base)
+	for _, c := range plan.Picks {
+		fmt.Fprintf(w, "  %s %s\n", c.ShortSHA(), strings.TrimSpace(c.FormatSubject(60)))
+	}
+
+	plan, err = p.Verify(plan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nApplied cleanly to branch %s at %s\n", plan.Branch, plan.HeadSHA)
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/multi"
+)
+
+// Exit codes of the commands. 2 is also used by flag for usage errors.
+const (
+	exitFullBump    = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitNothingBump = 3
+	exitPartialBump = 4
+	exitUnaccepted  = 5
+)
+
+func exitCode(r bumper.Result) int {
+	return statusExitCode(r.Status())
+}
+
+func statusExitCode(s bumper.Status) int {
+	switch s {
+	case bumper.FullBump:
+		return exitFullBump
+	case bumper.PartialBump:
+		return exitPartialBump
+	default:
+		return exitNothingBump
+	}
+}
+
+// reposExitCode combines the bumps of several repositories: the whole range
+// of every repository, nothing in any of them, or anything in between.
+func reposExitCode(results []multi.Result) int {
+	if multi.Failed(results) {
+		return exitError
+	}
+	return statusExitCode(multi.Status(results))
+}
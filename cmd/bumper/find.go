@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/notify"
+)
+
+// findFlags are the flags of the find and explain commands.
+type findFlags struct {
+	verbose         bool
+	check           bool
+	output          string
+	completeStories bool
+	openPR          bool
+	prBranch        string
+}
+
+func findCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	f := &findFlags{}
+	fs.BoolVar(
+		&f.verbose,
+		"verbose",
+		false,
+		"Output all the information, like the explain command.",
+	)
+	f.register(fs)
+
+	return func() int {
+		return find(g, f)
+	}
+}
+
+func explainCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	f := &findFlags{verbose: true}
+	f.register(fs)
+
+	return func() int {
+		return find(g, f)
+	}
+}
+
+func (f *findFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(
+		&f.check,
+		"check",
+		false,
+		"Only compute the bump and exit with 0 if the whole range can be bumped, 3 if nothing can, 4 if part of it can, or 1 on errors, without any output. With the repos of a config, 0 and 3 require every repo to bump all or nothing.",
+	)
+	fs.StringVar(
+		&f.output,
+		"output",
+		"table",
+		"Output format when bumping the repositories of a config: table or json.",
+	)
+	fs.BoolVar(
+		&f.completeStories,
+		"complete-stories",
+		false,
+		"When bumping the repositories of a config, hold back a story's commits in every repository unless all of them can bump past the story.",
+	)
+	fs.BoolVar(
+		&f.openPR,
+		"open-pr",
+		false,
+		"Open or update a pull request for the bump, like apply -pr.",
+	)
+	registerPRBranch(fs, &f.prBranch)
+}
+
+func find(g *globalFlags, f *findFlags) int {
+	if f.openPR && g.refuseOverrides("-open-pr") {
+		return exitUsage
+	}
+
+	e := g.env()
+
+	if len(e.cfg.Repos) > 0 && f.check {
+		results, err := runRepos(e, f.completeStories)
+		if err != nil {
+			log.Print(err)
+			return exitError
+		}
+		return reposExitCode(results)
+	}
+
+	if len(e.cfg.Repos) > 0 {
+		results, err := bumpRepos(e, f.output, f.completeStories)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return reposExitCode(results)
+	}
+
+	if f.check {
+		r, err := e.newBumper(e.commitRange(g.commitRange), logger.NewLogger()).Bump()
+		if err != nil {
+			log.Print(err)
+			return exitError
+		}
+		return exitCode(r)
+	}
+
+	var notifier *notify.Notifier
+	if !e.overrides {
+		var err error
+		notifier, err = newNotifier(e.cfg.Notify, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var loggers []bumper.Logger
+	if f.verbose {
+		var opts []logger.VerboseLoggerOption
+		if g.disableColor {
+			opts = append(opts, logger.WithColorDisabled())
+		}
+
+		loggers = append(loggers, logger.NewVerboseLogger(opts...))
+	}
+	if logger.IsGitHubActions() {
+		loggers = append(loggers, logger.NewGitHubActionsLogger())
+	}
+	if len(loggers) == 0 {
+		loggers = append(loggers, logger.NewLogger())
+	}
+	if notifier != nil {
+		loggers = append(loggers, notifier)
+	}
+	bumperLog := logger.NewMultiLogger(loggers...)
+
+	r, err := e.newBumper(e.commitRange(g.commitRange), bumperLog).FindBump()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if r.Freeze != nil {
+		log.Printf("release freeze %q is active", r.Freeze.Name)
+	}
+
+	if f.openPR {
+		err = openPullRequest(e.ghc, g.githubRepo, r, f.prBranch, e.targetBranch(g, r.CommitRange))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return exitCode(r)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/hook"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+func installHookCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	return func() int {
+		e := g.env()
+		err := installHook(e.gc, e.targetBranch(g, ""), hookArgs(fs, g))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return 0
+	}
+}
+
+func prePushCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	return func() int {
+		if g.refuseOverrides("pre-push") {
+			return exitUsage
+		}
+
+		e := g.env()
+
+		err := prePush(e.gc, e.targetBranch(g, ""), e.bumperOpts)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return 0
+	}
+}
+
+// allowPushEnv skips the pre-push check when set.
+const allowPushEnv = "BUMPER_ALLOW_PUSH"
+
+// installHook installs a pre-push hook running this executable with the
+// given global flags.
+func installHook(gc git.GitClient, targetBranch string, args []string) error {
+	hooksDir, err := gc.HooksDir()
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	command := fmt.Sprintf("%s pre-push -target-branch %s", shellQuote(exe), shellQuote(targetBranch))
+	for _, a := range args {
+		command += " " + shellQuote(a)
+	}
+	err = hook.Install(hooksDir, command)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("installed pre-push hook protecting %s in %s", targetBranch, hooksDir)
+	return nil
+}
+
+// prePush refuses pushes to the target branch beyond the bump SHA.
+func prePush(gc git.GitClient, targetBranch string, bumperOpts []bumper.BumperOption) error {
+	if os.Getenv(allowPushEnv) != "" {
+		return nil
+	}
+
+	updates, err := hook.ParseUpdates(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	p := hook.NewPrePush(targetBranch, func(commitRange string) hook.Bumper {
+		return bumper.New(commitRange, logger.NewLogger(),
+			append(bumperOpts, bumper.WithGitClient(gc))...,
+		)
+	})
+
+	err = p.Check(updates)
+	if err != nil {
+		return fmt.Errorf("%s (set %s=1 to push anyway)", err, allowPushEnv)
+	}
+	return nil
+}
+
+// hookArgs returns the global flags given to install-hook so the hook checks
+// pushes the same way. Paths are made absolute as the hook runs from the
+// top of the work tree. The per-run -accept and -reject are left out.
+func hookArgs(fs *flag.FlagSet, g *globalFlags) []string {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "target-branch", "accept", "reject":
+		case "config", "repo":
+			args = append(args, "-"+f.Name+"="+absPath(f.Value.String()))
+		case "submodule-mirror":
+			mirrors := submoduleMirrors(nil, g.mirrors)
+			var urls []string
+			for url := range mirrors {
+				urls = append(urls, url)
+			}
+			sort.Strings(urls)
+			for _, url := range urls {
+				args = append(args, "-"+f.Name+"="+url+"="+mirrors[url])
+			}
+		case "story-pattern":
+			for _, p := range g.storyPatterns {
+				args = append(args, "-"+f.Name+"="+p)
+			}
+		case "branch-pattern":
+			for _, p := range g.branchPatterns {
+				args = append(args, "-"+f.Name+"="+p)
+			}
+		default:
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	return args
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return abs
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
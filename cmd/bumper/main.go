@@ -1,38 +1,323 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
-	"log"
+	"time"
 
 	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/ci"
+	"github.com/loggregator/bumper/pkg/config"
+	"github.com/loggregator/bumper/pkg/freeze"
 	"github.com/loggregator/bumper/pkg/git"
-	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/github"
 	"github.com/loggregator/bumper/pkg/tracker"
 )
 
+// command is a subcommand of bumper. setup registers the command's flags
+// and returns the function running it, which returns the exit code.
+type command struct {
+	name    string
+	summary string
+	setup   func(fs *flag.FlagSet, g *globalFlags) func() int
+}
+
+var commands = []command{
+	{"find", "Print the commit to bump to. This is the default command.", findCommand},
+	{"explain", "Show every commit in the range and whether it can be bumped.", explainCommand},
+	{"apply", "Push the commit to bump to onto the target branch, or open a pull request for it.", applyCommand},
+	{"audit", "List commits on the target branch whose stories are not accepted, exiting with 5 if there are any.", auditCommand},
+	{"unblock", "List the stories to accept to bump to the end of the range.", unblockCommand},
+	{"cherry-pick", "Plan and verify cherry-picks of accepted commits past a blocker.", cherryPickCommand},
+	{"watch", "Recompute the bump periodically and print changes as JSON lines.", watchCommand},
+	{"serve", "Serve the bump status over HTTP.", serveCommand},
+	{"install-hook", "Install a pre-push hook protecting the target branch.", installHookCommand},
+	{"pre-push", "Run the pre-push check. Called by the installed hook.", prePushCommand},
+	{"version", "Print the version of bumper.", versionCommand},
+}
+
 func main() {
-	commitRange := flag.String(
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	name := "find"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		if len(args) == 0 {
+			usage(os.Stdout)
+			return 0
+		}
+		name, args = args[0], []string{"-h"}
+	}
+
+	cmd, ok := lookupCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("bumper "+cmd.name, flag.ExitOnError)
+	g := &globalFlags{}
+	g.register(fs)
+	globals := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) {
+		globals[f.Name] = true
+	})
+
+	runCommand := cmd.setup(fs, g)
+	fs.Usage = func() {
+		commandUsage(fs.Output(), cmd, fs, globals)
+	}
+	fs.Parse(args)
+
+	return runCommand()
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: bumper [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "bumper help <command>" for the flags of a command.`)
+}
+
+// commandUsage prints the flags of the command before the global flags.
+func commandUsage(w io.Writer, cmd command, fs *flag.FlagSet, globals map[string]bool) {
+	own := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	shared := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.VisitAll(func(f *flag.Flag) {
+		if globals[f.Name] {
+			shared.Var(f.Value, f.Name, f.Usage)
+			return
+		}
+		own.Var(f.Value, f.Name, f.Usage)
+	})
+	own.SetOutput(w)
+	shared.SetOutput(w)
+
+	fmt.Fprintf(w, "Usage: bumper %s [flags]\n\n%s\n", cmd.name, cmd.summary)
+	if hasFlags(own) {
+		fmt.Fprintln(w, "\nFlags:")
+		own.PrintDefaults()
+	}
+	fmt.Fprintln(w, "\nGlobal flags:")
+	shared.PrintDefaults()
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	has := false
+	fs.VisitAll(func(*flag.Flag) {
+		has = true
+	})
+	return has
+}
+
+// globalFlags are the flags shared by every command.
+type globalFlags struct {
+	commitRange    string
+	repoPath       string
+	fetch          bool
+	mirrors        stringsFlag
+	bareSubmodules bool
+	configPath     string
+	disableColor   bool
+	firstParent    bool
+	patchIDReverts bool
+	storyPatterns  stringsFlag
+	branchPatterns stringsFlag
+	branchStories  bool
+	accept         storiesFlag
+	reject         storiesFlag
+	minAcceptedAge time.Duration
+	ciStatus       string
+	githubRepo     string
+	githubAPIURL   string
+	targetBranch   string
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(
+		&g.commitRange,
 		"commit-range",
-		"master..release-elect",
-		"Specifies the commit range to consider bumping.",
+		"",
+		"Specifies the commit range to consider bumping, such as master..release-elect. Defaults to the commit_range of the config, or is detected from master, or else the default branch of origin, and the release branches.",
+	)
+	fs.StringVar(
+		&g.repoPath,
+		"repo",
+		"",
+		"Path to the repository. Defaults to the current directory.",
 	)
-	verbose := flag.Bool(
-		"verbose",
+	fs.BoolVar(
+		&g.fetch,
+		"fetch",
 		false,
-		"Output all the information.",
+		"Fetch the remotes used in the commit range and the followed submodules first, and detect the range from the branches of origin. A given range must use remote-tracking branches such as origin/main..origin/release-elect.",
 	)
-	disableColor := flag.Bool(
+	fs.Var(
+		&g.mirrors,
+		"submodule-mirror",
+		"Read the followed submodule with the URL from a bare mirror instead of its checkout, given as URL=PATH. May be repeated.",
+	)
+	fs.BoolVar(
+		&g.bareSubmodules,
+		"bare-submodules",
+		false,
+		"Read followed submodules without a -submodule-mirror from the modules directory of the git dir instead of their checkouts.",
+	)
+	fs.StringVar(
+		&g.configPath,
+		"config",
+		"",
+		"Path to a JSON config listing repositories to bump together, where to send notifications and release freezes.",
+	)
+	fs.BoolVar(
+		&g.disableColor,
 		"no-color",
 		false,
 		"Disable color.",
 	)
+	fs.BoolVar(
+		&g.firstParent,
+		"first-parent",
+		true,
+		"Only follow the first parent of merge commits, attributing merged commits to the merge.",
+	)
+	fs.BoolVar(
+		&g.patchIDReverts,
+		"patch-id-reverts",
+		false,
+		"Detect reverts by patch-id when the commit message does not reference the reverted commit. Runs four extra git commands per commit.",
+	)
+	fs.Var(
+		&g.storyPatterns,
+		"story-pattern",
+		"Regular expression matching a story ID in commit messages without a story tag. The first capture group must be the story ID. Merged branch names such as story/123456-fix-drain are only matched with -branch-stories or -branch-pattern. May be repeated.",
+	)
+	fs.Var(
+		&g.branchPatterns,
+		"branch-pattern",
+		"Regular expression matching a story ID in merged branch names. The first capture group must be the story ID. Branch names are not matched unless given or with -branch-stories. May be repeated.",
+	)
+	fs.BoolVar(
+		&g.branchStories,
+		"branch-stories",
+		false,
+		"Take the story ID of a merge from a number of six or more digits in the merged branch name, such as story/123456-fix-drain. Dates such as release-20190305 match as well.",
+	)
+	fs.Var(
+		&g.accept,
+		"accept",
+		"Treat the stories as accepted for this run without changing them in Tracker. Only for find without -open-pr, explain and unblock, and nothing is notified. Comma separated and may be repeated.",
+	)
+	fs.Var(
+		&g.reject,
+		"reject",
+		"Treat the stories as not accepted for this run without changing them in Tracker. Only for find without -open-pr, explain and unblock, and nothing is notified. Comma separated and may be repeated.",
+	)
+	fs.DurationVar(
+		&g.minAcceptedAge,
+		"min-accepted-age",
+		0,
+		"Treat stories accepted more recently than this as still blocking.",
+	)
+	fs.StringVar(
+		&g.ciStatus,
+		"ci-status",
+		"",
+		"Only bump to commits whose build is green: \"github\" to use commit statuses and check runs of -github-repo, or a URL with {sha} returning {\"state\": \"success\"}. For the repos of a config the github_repo of each repo is used, and {repo} in the URL is replaced by the repo name.",
+	)
+	fs.StringVar(
+		&g.githubRepo,
+		"github-repo",
+		os.Getenv("GITHUB_REPOSITORY"),
+		"GitHub repository (owner/name) for pull requests and commit statuses.",
+	)
+	fs.StringVar(
+		&g.githubAPIURL,
+		"github-api-url",
+		github.DefaultBaseURL,
+		"Base URL of the GitHub API.",
+	)
+	fs.StringVar(
+		&g.targetBranch,
+		"target-branch",
+		"",
+		"Branch that is bumped, audited and protected by the pre-push hook. Defaults to the branch at the start of the commit range, or the default branch of origin.",
+	)
+}
 
-	flag.Parse()
+// overrides reports whether -accept or -reject is given.
+func (g *globalFlags) overrides() bool {
+	return len(g.accept) > 0 || len(g.reject) > 0
+}
+
+// refuseOverrides reports a usage error if -accept or -reject is given, as
+// their hypothetical acceptances must not reach what acts on the bump.
+func (g *globalFlags) refuseOverrides(what string) bool {
+	if !g.overrides() {
+		return false
+	}
+
+	fmt.Fprintf(os.Stderr, "-accept and -reject only apply to find, explain and unblock, not to %s\n", what)
+	return true
+}
+
+// env holds the clients configured by the global flags.
+type env struct {
+	cfg        config.Config
+	gitOpts    []git.ClientOption
+	gc         git.GitClient
+	tc         tracker.Client
+	stc        bumper.TrackerClient
+	ghc        *github.Client
+	bumperOpts []bumper.BumperOption
+	fetch      bool
+	overrides  bool
+
+	githubRepo   string
+	githubAPIURL string
+	ciStatus     string
+}
+
+// env configures the clients. The submodules to follow are read from the
+// FOLLOW_BUMPS_OF environment variable and the API tokens from
+// TRACKER_API_TOKEN and GITHUB_TOKEN.
+func (g *globalFlags) env() env {
+	var e env
+	if g.configPath != "" {
+		var err error
+		e.cfg, err = config.Load(g.configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	var submodulePaths []string
 	followBumpsOf := os.Getenv("FOLLOW_BUMPS_OF")
@@ -40,10 +325,39 @@ func main() {
 		submodulePaths = strings.Split(followBumpsOf, ",")
 	}
 
-	gc := git.NewClient(
-		git.WithCommandExecutor(cmdExecutor{}),
-		git.WithFollowBumpsOf(submodulePaths...),
-	)
+	e.gitOpts = []git.ClientOption{
+		git.WithCommandExecutor(git.ExecCommandExecutor{}),
+	}
+	if len(g.storyPatterns) > 0 {
+		e.gitOpts = append(e.gitOpts, git.WithStoryPatterns(compilePatterns(g.storyPatterns)...))
+	}
+	branchPatterns := compilePatterns(g.branchPatterns)
+	if g.branchStories {
+		branchPatterns = append(branchPatterns, git.BranchStoryPattern)
+	}
+	if len(branchPatterns) > 0 {
+		e.gitOpts = append(e.gitOpts, git.WithBranchPatterns(branchPatterns...))
+	}
+	if g.firstParent {
+		e.gitOpts = append(e.gitOpts, git.WithFirstParent())
+	}
+	if g.patchIDReverts {
+		e.gitOpts = append(e.gitOpts, git.WithPatchIDReverts())
+	}
+	if g.fetch {
+		e.gitOpts = append(e.gitOpts, git.WithRemoteTrackingRefs())
+	}
+	mirrors := submoduleMirrors(e.cfg.SubmoduleMirrors, g.mirrors)
+	if g.bareSubmodules || len(mirrors) > 0 {
+		e.gitOpts = append(e.gitOpts, git.WithSubmoduleMirrors(mirrors))
+	}
+	e.fetch = g.fetch
+
+	gcOpts := append(e.gitOpts, git.WithFollowBumpsOf(submodulePaths...))
+	if g.repoPath != "" {
+		gcOpts = append(gcOpts, git.WithRepoPath(g.repoPath))
+	}
+	e.gc = git.NewClient(gcOpts...)
 
 	var httpClient tracker.HTTPClient = http.DefaultClient
 
@@ -52,43 +366,235 @@ func main() {
 		httpClient = tracker.NewAPIHTTPClient(http.DefaultClient, apiToken)
 	}
 
-	tc := tracker.NewClient(tracker.WithHTTPClient(httpClient))
+	e.tc = tracker.NewClient(tracker.WithHTTPClient(httpClient))
+
+	e.ghc = github.NewClient(g.githubRepo,
+		github.WithBaseURL(g.githubAPIURL),
+		github.WithToken(os.Getenv("GITHUB_TOKEN")),
+	)
 
-	var bumperLog bumper.Logger = logger.NewLogger()
-	if *verbose {
-		var opts []logger.VerboseLoggerOption
-		if *disableColor {
-			opts = append(opts, logger.WithColorDisabled())
+	e.stc = e.tc
+	e.overrides = g.overrides()
+	if e.overrides {
+		overrides := make(map[int]bool)
+		for _, id := range g.accept {
+			overrides[id] = true
+		}
+		for _, id := range g.reject {
+			overrides[id] = false
 		}
+		e.stc = tracker.NewOverrideClient(e.tc, overrides)
+	}
 
-		bumperLog = logger.NewVerboseLogger(opts...)
+	e.bumperOpts = []bumper.BumperOption{
+		bumper.WithTrackerClient(e.stc),
 	}
+	if g.minAcceptedAge > 0 {
+		e.bumperOpts = append(e.bumperOpts, bumper.WithMinAcceptedAge(e.tc, g.minAcceptedAge))
+	}
+	e.githubRepo = g.githubRepo
+	e.githubAPIURL = g.githubAPIURL
+	e.ciStatus = g.ciStatus
 
-	b := bumper.New(*commitRange, bumperLog,
-		bumper.WithGitClient(gc),
-		bumper.WithTrackerClient(tc),
-	)
-	err := b.FindBumpSHA()
+	if len(e.cfg.Freezes) > 0 {
+		schedule, err := freeze.NewSchedule(e.cfg.Freezes)
+		if err != nil {
+			log.Fatal(err)
+		}
+		e.bumperOpts = append(e.bumperOpts, bumper.WithFreezes(schedule, e.tc))
+	}
+
+	return e
+}
+
+// commitRange returns the commit range given by -commit-range or the config,
+// or detects it from the branches of the repository, fetching first with
+// -fetch. It exits when either end of the range does not exist, or with a
+// usage error when -fetch is given for a range of local branches.
+func (e env) commitRange(flagRange string) string {
+	commitRange, err := resolveRange(e.gc, e.fetch, flagRange, e.cfg.CommitRange)
+	var ue usageError
+	if errors.As(err, &ue) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitUsage)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+	return commitRange
 }
 
-type cmdExecutor struct{}
+// usageError is an error in the flags given.
+type usageError struct {
+	error
+}
 
-func (c cmdExecutor) Run(cmd *exec.Cmd) error {
-	stderrBuf := &strings.Builder{}
-	cmd.Stderr = stderrBuf
-	err := cmd.Run()
+// targetBranch returns the branch given by -target-branch, or the branch at
+// the start of the commit range. With an empty range the range given by
+// -commit-range or the config is used without detecting it.
+func (e env) targetBranch(g *globalFlags, commitRange string) string {
+	if g.targetBranch != "" {
+		return g.targetBranch
+	}
+	if commitRange == "" {
+		commitRange = firstRange(g.commitRange, e.cfg.CommitRange)
+	}
 
+	branch, err := e.gc.TargetBranch(commitRange)
 	if err != nil {
-		return fmt.Errorf(
-			`failed to execute "%s": %s (stderr: "%s")`,
-			strings.Join(cmd.Args, " "),
-			err,
-			strings.TrimRight(stderrBuf.String(), "\r\n"),
+		log.Fatal(err)
+	}
+	return branch
+}
+
+// resolveRange returns the first of the configured commit ranges that is
+// set, or detects it from the branches of the repository. As fetching only
+// updates remote-tracking branches, a range of local branches is refused
+// when fetching.
+func resolveRange(gc git.GitClient, fetch bool, configured ...string) (string, error) {
+	commitRange := firstRange(configured...)
+
+	if fetch {
+		err := gc.FetchRange(commitRange)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var err error
+	if commitRange == "" {
+		commitRange, err = gc.DetectRange(git.DefaultReleaseBranches)
+	} else {
+		err = gc.ValidateRange(commitRange)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !fetch {
+		return commitRange, nil
+	}
+	if local := gc.LocalBranches(commitRange); len(local) > 0 {
+		return "", usageError{fmt.Errorf(
+			"-fetch only updates remote-tracking branches, but the commit range %s uses the local branches %s; use a range such as origin/main..origin/release-elect",
+			commitRange, strings.Join(local, ", "),
+		)}
+	}
+	return commitRange, nil
+}
+
+func firstRange(ranges ...string) string {
+	for _, r := range ranges {
+		if r != "" {
+			return r
+		}
+	}
+	return ""
+}
+
+// newBumper returns a bumper for the commit range of the repository.
+func (e env) newBumper(commitRange string, l bumper.Logger) bumper.Bumper {
+	opts, err := e.statusOpts(e.githubRepo, "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return bumper.New(commitRange, l,
+		append(append(opts, e.bumperOpts...), bumper.WithGitClient(e.gc))...,
+	)
+}
+
+// statusOpts returns the options checking the builds of a repository as
+// given by -ci-status. On GitHub the commit statuses of githubRepo are
+// checked, otherwise {repo} in the URL is replaced by name.
+func (e env) statusOpts(githubRepo, name string) ([]bumper.BumperOption, error) {
+	switch e.ciStatus {
+	case "":
+		return nil, nil
+	case "github":
+		if githubRepo == "" {
+			return nil, errors.New("-github-repo or the github_repo of the config is required to check commit statuses on GitHub")
+		}
+		ghc := github.NewClient(githubRepo,
+			github.WithBaseURL(e.githubAPIURL),
+			github.WithToken(os.Getenv("GITHUB_TOKEN")),
 		)
+		return []bumper.BumperOption{bumper.WithStatusChecker(ghc)}, nil
+	default:
+		url := strings.ReplaceAll(e.ciStatus, "{repo}", name)
+		return []bumper.BumperOption{bumper.WithStatusChecker(ci.NewHTTPChecker(url))}, nil
+	}
+}
+
+// submoduleMirrors merges the mirrors of the config with those given as
+// URL=PATH flags. The paths are made absolute as git runs in the
+// repository.
+func submoduleMirrors(configured map[string]string, flags []string) map[string]string {
+	mirrors := make(map[string]string)
+	for url, path := range configured {
+		mirrors[url] = path
+	}
+	for _, f := range flags {
+		i := strings.LastIndex(f, "=")
+		if i < 1 {
+			log.Fatalf("invalid -submodule-mirror %q, expected URL=PATH", f)
+		}
+		mirrors[f[:i]] = f[i+1:]
+	}
+
+	for url, path := range mirrors {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		mirrors[url] = abs
 	}
+	return mirrors
+}
+
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+type storiesFlag []int
+
+func (s *storiesFlag) String() string {
+	var ids []string
+	for _, id := range *s {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return strings.Join(ids, ",")
+}
 
+func (s *storiesFlag) Set(v string) error {
+	for _, id := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(id), "#"))
+		if err != nil {
+			return fmt.Errorf("invalid story ID %q", id)
+		}
+		*s = append(*s, n)
+	}
 	return nil
 }
+
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			log.Fatalf("invalid pattern %q: %s", p, err)
+		}
+		if re.NumSubexp() == 0 {
+			log.Fatalf("invalid pattern %q: it needs a capture group for the story ID", p)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled
+}
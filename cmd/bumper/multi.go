@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/config"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/multi"
+)
+
+// bumpRepos computes the bumps of all repos in the config concurrently and
+// writes them in the given output format. Repos that failed are part of the
+// output and the results. Nothing is notified with -accept or -reject.
+func bumpRepos(e env, output string, completeStories bool) ([]multi.Result, error) {
+	cfg := e.cfg
+
+	results, err := runRepos(e, completeStories)
+	if err != nil {
+		return nil, err
+	}
+
+	switch output {
+	case "table":
+		err = multi.WriteTable(os.Stdout, results)
+	case "json":
+		err = multi.WriteJSON(os.Stdout, results)
+	default:
+		err = fmt.Errorf("unknown output format %q", output)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range results {
+		if r.Err != nil || e.overrides {
+			continue
+		}
+
+		n, err := newNotifier(cfg.Notify, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		if n == nil {
+			break
+		}
+
+		err = n.Notify(r.Result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// runRepos computes the bumps of all repos in the config concurrently.
+func runRepos(e env, completeStories bool) ([]multi.Result, error) {
+	var repos []multi.Repo
+	for _, r := range e.cfg.Repos {
+		b, _, err := newRepoBumper(r, e)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, multi.Repo{
+			Name:   r.Name,
+			Path:   r.Path,
+			Bumper: b,
+		})
+	}
+
+	results := multi.Run(repos)
+	if completeStories {
+		results = multi.CompleteStories(results)
+	}
+	return results, nil
+}
+
+// newRepoBumper returns the bumper of a repo of the config and a fetcher
+// updating its commit range.
+func newRepoBumper(r config.Repo, e env) (bumper.Bumper, rangeFetcher, error) {
+	gc := git.NewClient(append(
+		e.gitOpts,
+		git.WithRepoPath(r.Path),
+		git.WithFollowBumpsOf(r.FollowBumpsOf...),
+	)...)
+
+	commitRange, err := resolveRange(gc, e.fetch, r.CommitRange)
+	if err != nil {
+		return bumper.Bumper{}, rangeFetcher{}, fmt.Errorf("repo %s: %s", r.Name, err)
+	}
+
+	opts, err := e.statusOpts(r.GitHubRepo, r.Name)
+	if err != nil {
+		return bumper.Bumper{}, rangeFetcher{}, fmt.Errorf("repo %s: %s", r.Name, err)
+	}
+
+	return bumper.New(commitRange, logger.NewLogger(),
+		append(append(opts, e.bumperOpts...), bumper.WithGitClient(gc))...,
+	), rangeFetcher{gc: gc, commitRange: commitRange}, nil
+}
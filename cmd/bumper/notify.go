@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/config"
+	"github.com/loggregator/bumper/pkg/notify"
+)
+
+// newNotifier returns a notifier for the configured sinks, or nil if no
+// sinks are configured. The name distinguishes the state of notifiers for
+// different repositories. The SMTP password may be given by the
+// SMTP_PASSWORD environment variable.
+func newNotifier(cfg config.Notify, name string) (*notify.Notifier, error) {
+	var sinks []notify.Sink
+	if cfg.SlackWebhookURL != "" {
+		sinks = append(sinks, notify.NewSlackSink(cfg.SlackWebhookURL, http.DefaultClient))
+	}
+	if cfg.WebhookURL != "" {
+		sinks = append(sinks, notify.NewWebhookSink(cfg.WebhookURL, http.DefaultClient))
+	}
+	if cfg.Email != nil {
+		var auth smtp.Auth
+		if cfg.Email.Username != "" {
+			password := cfg.Email.Password
+			if password == "" {
+				password = os.Getenv("SMTP_PASSWORD")
+			}
+			host, _, _ := net.SplitHostPort(cfg.Email.Addr)
+			auth = smtp.PlainAuth("", cfg.Email.Username, password, host)
+		}
+
+		sinks = append(sinks, notify.NewEmailSink(
+			cfg.Email.Addr,
+			auth,
+			cfg.Email.From,
+			cfg.Email.To,
+		))
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	var opts []notify.NotifierOption
+	if cfg.BlockedAfter != 0 {
+		opts = append(opts, notify.WithBlockedAfter(time.Duration(cfg.BlockedAfter)))
+	}
+	if cfg.StateFile != "" {
+		path := cfg.StateFile
+		if name != "" {
+			path += "." + name
+		}
+		opts = append(opts, notify.WithStore(notify.NewFileStore(path)))
+	}
+	for kind, text := range cfg.Templates {
+		opts = append(opts, notify.WithTemplate(kind, text))
+	}
+
+	return notify.New(sinks, opts...)
+}
+
+// notifyingBumper notifies about every bump it computes.
+type notifyingBumper struct {
+	bumper interface {
+		Bump() (bumper.Result, error)
+	}
+	notifier *notify.Notifier
+}
+
+func (b notifyingBumper) Bump() (bumper.Result, error) {
+	r, err := b.bumper.Bump()
+	if err != nil {
+		return r, err
+	}
+
+	return r, b.notifier.Notify(r)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/github"
+	"github.com/loggregator/bumper/pkg/report"
+)
+
+const defaultPRBranch = "bumper/release"
+
+func registerPRBranch(fs *flag.FlagSet, branch *string) {
+	fs.StringVar(
+		branch,
+		"pr-branch",
+		defaultPRBranch,
+		"Branch pointed at the bump SHA that the pull request is opened from.",
+	)
+}
+
+// openPullRequest points branch at the bump SHA and opens or updates a pull
+// request from it into the target branch of repo.
+func openPullRequest(c *github.Client, repo string, r bumper.Result, branch, target string) error {
+	if repo == "" {
+		return errors.New("-github-repo is required to open a pull request")
+	}
+	if r.BumpSHA == "" {
+		log.Print("nothing to bump, not opening a pull request")
+		return nil
+	}
+
+	err := c.UpdateBranch(branch, r.BumpSHA)
+	if err != nil {
+		return err
+	}
+
+	pr, err := c.OpenPullRequest(github.PullRequest{
+		Title: fmt.Sprintf("Bump %s to %.8s", target, r.BumpSHA),
+		Body:  report.Markdown(r) + report.Stories(r),
+		Head:  branch,
+		Base:  target,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("opened pull request %s", pr.URL)
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/server"
+)
+
+func serveCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	interval := fs.Duration(
+		"interval",
+		5*time.Minute,
+		"How often to recompute the bumps, fetching their ranges first with -fetch.",
+	)
+	addr := fs.String(
+		"addr",
+		":8080",
+		"Address to listen on.",
+	)
+
+	return func() int {
+		if g.refuseOverrides("serve") {
+			return exitUsage
+		}
+
+		e := g.env()
+
+		var ranges []server.Range
+		for _, r := range e.cfg.Repos {
+			b, f, err := newRepoBumper(r, e)
+			if err != nil {
+				log.Fatal(err)
+			}
+			ranges = append(ranges, e.serverRange(r.Name, b, f))
+		}
+		if len(ranges) == 0 {
+			commitRange := e.commitRange(g.commitRange)
+			ranges = append(ranges, e.serverRange(
+				"default",
+				e.newBumper(commitRange, logger.NewLogger()),
+				rangeFetcher{gc: e.gc, commitRange: commitRange},
+			))
+		}
+
+		log.Fatal(serve(ranges, e.tc, *addr, *interval))
+		return exitError
+	}
+}
+
+// serverRange returns a range of the server that is fetched before each
+// periodic recompute with -fetch.
+func (e env) serverRange(name string, b server.Bumper, f rangeFetcher) server.Range {
+	r := server.Range{
+		Name:   name,
+		Bumper: b,
+	}
+	if e.fetch {
+		r.Fetcher = f
+	}
+	return r
+}
+
+// serve recomputes the bumps of the ranges in the background and serves
+// their status on addr. Tracker webhooks are authenticated with the
+// TRACKER_WEBHOOK_TOKEN environment variable when it is set.
+func serve(ranges []server.Range, c server.Cache, addr string, interval time.Duration) error {
+	s := server.New(ranges,
+		server.WithCache(c),
+		server.WithInterval(interval),
+		server.WithWebhookToken(os.Getenv("TRACKER_WEBHOOK_TOKEN")),
+	)
+
+	go s.Run(make(chan struct{}))
+
+	log.Printf("serving bump status on %s", addr)
+	return http.ListenAndServe(addr, s.Handler())
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+func unblockCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	output := fs.String(
+		"output",
+		"table",
+		"Output format: table or json.",
+	)
+
+	return func() int {
+		e := g.env()
+
+		r, err := e.newBumper(e.commitRange(g.commitRange), logger.NewLogger()).Bump()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = writeUnblock(os.Stdout, r, *output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return 0
+	}
+}
+
+// writeUnblock writes which stories need to be accepted to bump to the end
+// of the range in the given output format.
+func writeUnblock(w io.Writer, r bumper.Result, output string) error {
+	u := r.Unblock()
+
+	switch output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(u)
+	case "table":
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	if len(u.Stories) == 0 {
+		if u.Reachable {
+			fmt.Fprintf(w, "Nothing is blocking the bump to the end of %s.\n", r.CommitRange)
+		} else {
+			fmt.Fprintf(w, "No stories are blocking %s, but it can not be bumped to its end.\n", r.CommitRange)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(w, "Accept these stories to bump to the end of %s:\n\n", r.CommitRange)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORY\tUNLOCKS\tREQUIRED\tNAME")
+	for _, s := range u.Stories {
+		required := "no"
+		if s.Required {
+			required = "yes"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\n", s.StoryID, s.Unlocks, required, s.StoryName)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	if !u.Reachable {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Accepting them is not enough: some commits are frozen, soaking or have a red build.")
+	}
+	return nil
+}
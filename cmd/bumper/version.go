@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+// version is set when building with -ldflags "-X main.version=<version>".
+var version = "dev"
+
+func versionCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	return func() int {
+		fmt.Println(version)
+		return 0
+	}
+}
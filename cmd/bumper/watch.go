@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/watch"
+)
+
+func watchCommand(fs *flag.FlagSet, g *globalFlags) func() int {
+	interval := fs.Duration(
+		"interval",
+		5*time.Minute,
+		"How often to fetch and recompute the bump. The commit range must use remote-tracking branches, which are detected when -commit-range is not set.",
+	)
+
+	return func() int {
+		if g.refuseOverrides("watch") {
+			return exitUsage
+		}
+
+		// fetching only updates remote-tracking refs
+		g.fetch = true
+		e := g.env()
+
+		commitRange := e.commitRange(g.commitRange)
+
+		notifier, err := newNotifier(e.cfg.Notify, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var b watch.Bumper = e.newBumper(commitRange, logger.NewLogger())
+		if notifier != nil {
+			b = notifyingBumper{bumper: b, notifier: notifier}
+		}
+
+		watchBump(b, rangeFetcher{gc: e.gc, commitRange: commitRange}, e.tc, *interval)
+		return 0
+	}
+}
+
+// rangeFetcher fetches the remotes and followed submodules of a commit
+// range.
+type rangeFetcher struct {
+	gc          git.GitClient
+	commitRange string
+}
+
+func (f rangeFetcher) Fetch() error {
+	return f.gc.FetchRange(f.commitRange)
+}
+
+// watchBump recomputes the bump every interval and writes an event as a
+// line of JSON to stdout whenever it changes.
+func watchBump(
+	b watch.Bumper,
+	f watch.Fetcher,
+	c watch.Cache,
+	interval time.Duration,
+) {
+	enc := json.NewEncoder(os.Stdout)
+	w := watch.New(b, func(e watch.Event) {
+		err := enc.Encode(e)
+		if err != nil {
+			log.Printf("failed to write event: %s", err)
+		}
+	},
+		watch.WithFetcher(f),
+		watch.WithCache(c),
+		watch.WithInterval(interval),
+	)
+
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		close(stop)
+	}()
+
+	w.Run(stop)
+}
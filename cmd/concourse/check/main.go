@@ -0,0 +1,36 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/loggregator/bumper/pkg/concourse"
+)
+
+func main() {
+	var req concourse.CheckRequest
+	err := json.NewDecoder(os.Stdin).Decode(&req)
+	if err != nil {
+		log.Fatalf("failed to parse request: %s", err)
+	}
+
+	// keep a clone per repository between checks
+	dir := filepath.Join(
+		os.TempDir(),
+		fmt.Sprintf("bumper-resource-%x", sha1.Sum([]byte(req.Source.URI))),
+	)
+
+	versions, err := concourse.NewFromSource(req.Source).Check(req, dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(versions)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/loggregator/bumper/pkg/concourse"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("usage: %s <destination>", os.Args[0])
+	}
+
+	var req concourse.InRequest
+	err := json.NewDecoder(os.Stdin).Decode(&req)
+	if err != nil {
+		log.Fatalf("failed to parse request: %s", err)
+	}
+
+	resp, err := concourse.NewFromSource(req.Source).In(req, os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(resp)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/loggregator/bumper/pkg/concourse"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("usage: %s <sources>", os.Args[0])
+	}
+
+	var req concourse.OutRequest
+	err := json.NewDecoder(os.Stdin).Decode(&req)
+	if err != nil {
+		log.Fatalf("failed to parse request: %s", err)
+	}
+
+	workDir, err := ioutil.TempDir("", "bumper-resource-out")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := concourse.NewFromSource(req.Source).Out(req, os.Args[1], workDir)
+	os.RemoveAll(workDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(resp)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,6 +1,10 @@
 package bumper
 
 import (
+	"strings"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/freeze"
 	"github.com/loggregator/bumper/pkg/git"
 )
 
@@ -9,8 +13,23 @@ type GitClient interface {
 }
 
 type TrackerClient interface {
-	IsAccepted(storyID int) bool
-	Name(storyID int) string
+	IsAccepted(storyID int) (bool, error)
+	Name(storyID int) (string, error)
+}
+
+// StatusChecker reports whether the CI build of a commit is green.
+type StatusChecker interface {
+	Green(sha string) (bool, error)
+}
+
+// AcceptanceTimer reports when a story was accepted.
+type AcceptanceTimer interface {
+	AcceptedAt(storyID int) (time.Time, error)
+}
+
+// Labeler returns the labels of a story.
+type Labeler interface {
+	Labels(storyID int) ([]string, error)
 }
 
 type Logger interface {
@@ -19,10 +38,22 @@ type Logger interface {
 	Footer(bumpSHA string)
 }
 
+// FreezeLogger is a Logger that is told about the active release freeze
+// before the commits are logged.
+type FreezeLogger interface {
+	Freeze(w freeze.Window)
+}
+
 type Bumper struct {
 	commitRange string
 	gc          GitClient
 	tc          TrackerClient
+	sc          StatusChecker
+	at          AcceptanceTimer
+	minAge      time.Duration
+	freezes     freeze.Schedule
+	labeler     Labeler
+	now         func() time.Time
 	log         Logger
 }
 
@@ -30,6 +61,7 @@ func New(commitRange string, log Logger, opts ...BumperOption) Bumper {
 	b := Bumper{
 		commitRange: commitRange,
 		log:         log,
+		now:         time.Now,
 	}
 
 	for _, o := range opts {
@@ -39,33 +71,288 @@ func New(commitRange string, log Logger, opts ...BumperOption) Bumper {
 	return b
 }
 
+// Result is the outcome of computing a bump for a commit range.
+type Result struct {
+	CommitRange string
+	// Commits are the commits in the range, newest first.
+	Commits []*git.Commit
+	// BumpSHA is the commit to bump to, or empty if there is none.
+	BumpSHA string
+	// Freeze is the release freeze that is active, if any.
+	Freeze *freeze.Window
+
+	checker StatusChecker
+}
+
 func (b Bumper) FindBumpSHA() error {
+	_, err := b.FindBump()
+	return err
+}
+
+// FindBump computes the commit to bump to, logs it and returns the result.
+func (b Bumper) FindBump() (Result, error) {
 	b.log.Header(b.commitRange)
 
+	r, err := b.Bump()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if fl, ok := b.log.(FreezeLogger); ok && r.Freeze != nil {
+		fl.Freeze(*r.Freeze)
+	}
+	for _, c := range r.Commits {
+		b.log.Commit(c)
+	}
+
+	b.log.Footer(r.BumpSHA)
+	return r, nil
+}
+
+// Bump computes the commit to bump to without logging.
+func (b Bumper) Bump() (Result, error) {
+	r := Result{
+		CommitRange: b.commitRange,
+	}
+	if w, ok := b.freezes.Active(b.now()); ok {
+		r.Freeze = &w
+	}
+
 	commitsDesc, err := b.gc.Commits(b.commitRange)
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 
 	if len(commitsDesc) == 0 {
-		b.log.Footer("")
-		return nil
+		return r, nil
 	}
 
 	for _, c := range commitsDesc {
-		c.Accepted = b.tc.IsAccepted(c.StoryID)
-		c.StoryName = b.tc.Name(c.StoryID)
+		for _, mc := range c.WithMerged() {
+			err = b.judge(mc, r.Freeze)
+			if err != nil {
+				return Result{}, err
+			}
+		}
 	}
 
-	for _, c := range commitsDesc {
-		b.log.Commit(c)
+	commitsAsc := reverse(commitsDesc)
+	pairs := revertPairs(commitsAsc)
+	for _, c := range commitsAsc {
+		_, c.Reverted = pairs[c.Hash]
+	}
+
+	r.Commits = commitsDesc
+	r.checker = b.sc
+	r.BumpSHA, err = r.findGreenBump(commitsAsc, nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return r, nil
+}
+
+// findGreenBump returns the bump of the commits, walking back past commits
+// whose build is not green when a status checker is configured.
+func (r Result) findGreenBump(commitsAsc []*git.Commit, held map[int]bool) (string, error) {
+	bumpSHA := findBump(commitsAsc, held)
+	for r.checker != nil && bumpSHA != "" {
+		green, err := r.checker.Green(bumpSHA)
+		if err != nil {
+			return "", err
+		}
+		if green {
+			break
+		}
+
+		for _, c := range commitsAsc {
+			if c.Hash == bumpSHA {
+				c.CIFailed = true
+			}
+		}
+		bumpSHA = findBump(commitsAsc, held)
+	}
+
+	return bumpSHA, nil
+}
+
+// judge looks up the story of the commit and whether it may be bumped.
+func (b Bumper) judge(c *git.Commit, w *freeze.Window) error {
+	var err error
+	c.Accepted, err = b.tc.IsAccepted(c.StoryID)
+	if err != nil {
+		return err
+	}
+	c.StoryName, err = b.tc.Name(c.StoryID)
+	if err != nil {
+		return err
+	}
+
+	if c.Accepted {
+		soaking, err := b.soaking(c.StoryID)
+		if err != nil {
+			return err
+		}
+		if soaking {
+			c.Accepted = false
+			c.Soaking = true
+		}
+	}
+
+	if c.Accepted && w != nil {
+		allowed, err := b.allowed(*w, c.StoryID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			c.Accepted = false
+			c.Frozen = true
+		}
 	}
 
-	sha := findBump(reverse(commitsDesc))
-	b.log.Footer(sha)
 	return nil
 }
 
+// soaking reports whether the story was accepted more recently than the
+// minimum accepted age.
+func (b Bumper) soaking(storyID int) (bool, error) {
+	if b.at == nil || storyID == 0 {
+		return false, nil
+	}
+
+	acceptedAt, err := b.at.AcceptedAt(storyID)
+	if err != nil || acceptedAt.IsZero() {
+		return false, err
+	}
+
+	return b.now().Sub(acceptedAt) < b.minAge, nil
+}
+
+// allowed reports whether the story may be bumped during the freeze.
+func (b Bumper) allowed(w freeze.Window, storyID int) (bool, error) {
+	if b.labeler == nil || storyID == 0 {
+		return false, nil
+	}
+
+	labels, err := b.labeler.Labels(storyID)
+	if err != nil {
+		return false, err
+	}
+	return w.Allows(labels), nil
+}
+
+// Status is how much of the range can be bumped.
+type Status int
+
+const (
+	NothingToBump Status = iota
+	PartialBump
+	FullBump
+)
+
+// Status returns whether nothing, some or all of the range can be bumped.
+func (r Result) Status() Status {
+	switch {
+	case r.BumpSHA == "":
+		return NothingToBump
+	case r.BumpSHA == r.Commits[0].Hash:
+		return FullBump
+	default:
+		return PartialBump
+	}
+}
+
+// Hold returns the result of bumping the same commits while treating the
+// commits of the held stories as not accepted. The build of the new bump is
+// checked like in Bump.
+func (r Result) Hold(held map[int]bool) (Result, error) {
+	if len(r.Commits) == 0 {
+		return r, nil
+	}
+
+	var err error
+	r.BumpSHA, err = r.findGreenBump(reverse(r.Commits), held)
+	if err != nil {
+		return Result{}, err
+	}
+	return r, nil
+}
+
+// Remaining returns the commits, including merged commits, that are not
+// part of the bump.
+func (r Result) Remaining() []*git.Commit {
+	included := r.included()
+
+	var remaining []*git.Commit
+	for _, c := range r.Commits {
+		if !included[c.Hash] {
+			remaining = append(remaining, c.WithMerged()...)
+		}
+	}
+	return remaining
+}
+
+// Bumpable returns the commits, including merged commits, that are part of
+// the bump.
+func (r Result) Bumpable() []*git.Commit {
+	included := r.included()
+
+	var bumpable []*git.Commit
+	for _, c := range r.Commits {
+		if included[c.Hash] {
+			bumpable = append(bumpable, c.WithMerged()...)
+		}
+	}
+	return bumpable
+}
+
+// Unaccepted returns all commits, including merged commits, whose stories
+// are not accepted regardless of whether they are part of the bump.
+func (r Result) Unaccepted() []*git.Commit {
+	var unaccepted []*git.Commit
+	for _, c := range r.Commits {
+		for _, mc := range c.WithMerged() {
+			if !mc.Accepted && !mc.Reverted {
+				unaccepted = append(unaccepted, mc)
+			}
+		}
+	}
+	return unaccepted
+}
+
+// SkippedForCI returns the commits that were not bumped to because their
+// build is not green.
+func (r Result) SkippedForCI() []*git.Commit {
+	var skipped []*git.Commit
+	for _, c := range r.Commits {
+		if c.CIFailed {
+			skipped = append(skipped, c)
+		}
+	}
+	return skipped
+}
+
+// Blockers returns the remaining commits whose stories are not accepted.
+func (r Result) Blockers() []*git.Commit {
+	var blockers []*git.Commit
+	for _, c := range r.Remaining() {
+		if !c.Accepted && !c.Reverted {
+			blockers = append(blockers, c)
+		}
+	}
+	return blockers
+}
+
+func (r Result) included() map[string]bool {
+	commitsAsc := reverse(r.Commits)
+	for i, c := range commitsAsc {
+		if c.Hash == r.BumpSHA {
+			return newAncestry(commitsAsc).of(i)
+		}
+	}
+	return map[string]bool{}
+}
+
 func reverse(commits []*git.Commit) []*git.Commit {
 	reversed := make([]*git.Commit, len(commits))
 	for i, c := range commits {
@@ -74,42 +361,147 @@ func reverse(commits []*git.Commit) []*git.Commit {
 	return reversed
 }
 
-func findBump(commits []*git.Commit) string {
-	invalid := make(map[int]bool)
-	firstUnaccepted := -1
-	bumpHash := ""
+// findBump returns the newest commit whose ancestry within the range can be
+// bumped to: every commit in it is accepted and none of its stories have
+// commits left out of it. Commits whose build failed are never bumped to.
+func findBump(commits []*git.Commit, held map[int]bool) string {
+	pairs := revertPairs(commits)
+	ancestry := newAncestry(commits)
 
-	// find invalid index
-	for i, c := range commits {
-		if !c.Accepted {
-			firstUnaccepted = i
-			break
+	for i := len(commits) - 1; i >= 0; i-- {
+		if commits[i].CIFailed {
+			continue
+		}
+		if bumpable(commits, ancestry.of(i), pairs, held) {
+			return commits[i].Hash
 		}
 	}
 
-	// return early if all stories are accepted
-	if firstUnaccepted == -1 {
-		// this shouldn't panic since len(commits) is always > 0
-		return commits[len(commits)-1].Hash
+	return ""
+}
+
+func bumpable(
+	commits []*git.Commit,
+	included map[string]bool,
+	pairs map[string]string,
+	held map[int]bool,
+) bool {
+	stories := make(map[int]bool)
+	for _, c := range commits {
+		if !included[c.Hash] {
+			continue
+		}
+
+		// a reverted commit is neutral together with its revert, otherwise
+		// it is judged on its own acceptance
+		if partner, ok := pairs[c.Hash]; ok && included[partner] {
+			continue
+		}
+
+		for _, mc := range c.WithMerged() {
+			if !mc.Accepted || held[mc.StoryID] {
+				return false
+			}
+			if mc.StoryID != 0 {
+				stories[mc.StoryID] = true
+			}
+		}
 	}
 
-	// record invalid stories
-	for _, c := range commits[firstUnaccepted:] {
-		if c.Accepted && c.StoryID != 0 {
-			invalid[c.StoryID] = true
+	// stories must not be split across the bump
+	for _, c := range commits {
+		if included[c.Hash] {
+			continue
+		}
+		if _, ok := pairs[c.Hash]; ok {
+			continue
+		}
+
+		for _, mc := range c.WithMerged() {
+			if stories[mc.StoryID] {
+				return false
+			}
 		}
 	}
 
-	// find last commit that is accpeted and not invalid
-	for _, c := range commits[:firstUnaccepted] {
-		_, ok := invalid[c.StoryID]
-		if ok {
-			break
+	return true
+}
+
+type ancestry struct {
+	commits []*git.Commit
+	index   map[string]int
+}
+
+func newAncestry(commits []*git.Commit) ancestry {
+	index := make(map[string]int)
+	for i, c := range commits {
+		index[c.Hash] = i
+	}
+
+	return ancestry{
+		commits: commits,
+		index:   index,
+	}
+}
+
+// of returns the hashes of the commit at index i and its ancestors within
+// the range. Commits with unknown parents are treated as children of the
+// previous commit.
+func (a ancestry) of(i int) map[string]bool {
+	included := make(map[string]bool)
+	stack := []int{i}
+	for len(stack) > 0 {
+		j := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		c := a.commits[j]
+		if included[c.Hash] {
+			continue
+		}
+		included[c.Hash] = true
+
+		if c.Parents == nil {
+			if j > 0 {
+				stack = append(stack, j-1)
+			}
+			continue
+		}
+
+		for _, p := range c.Parents {
+			if k, ok := a.index[p]; ok {
+				stack = append(stack, k)
+			}
 		}
-		bumpHash = c.Hash
 	}
 
-	return bumpHash
+	return included
+}
+
+// revertPairs finds commits that are reverted later in the range and maps
+// each of them to its revert and vice versa.
+func revertPairs(commits []*git.Commit) map[string]string {
+	pairs := make(map[string]string)
+	for i, revert := range commits {
+		if revert.Reverts == "" {
+			continue
+		}
+		if _, ok := pairs[revert.Hash]; ok {
+			continue
+		}
+
+		for _, c := range commits[:i] {
+			if !strings.HasPrefix(c.Hash, revert.Reverts) {
+				continue
+			}
+			if _, ok := pairs[c.Hash]; ok {
+				break
+			}
+			pairs[c.Hash] = revert.Hash
+			pairs[revert.Hash] = c.Hash
+			break
+		}
+	}
+	return pairs
 }
 
 type BumperOption func(b *Bumper)
@@ -125,3 +517,35 @@ func WithTrackerClient(tc TrackerClient) BumperOption {
 		b.tc = tc
 	}
 }
+
+// WithStatusChecker only bumps to commits whose build is green, walking back
+// from the newest bumpable commit.
+func WithStatusChecker(sc StatusChecker) BumperOption {
+	return func(b *Bumper) {
+		b.sc = sc
+	}
+}
+
+// WithMinAcceptedAge treats stories accepted more recently than age as not
+// accepted yet.
+func WithMinAcceptedAge(at AcceptanceTimer, age time.Duration) BumperOption {
+	return func(b *Bumper) {
+		b.at = at
+		b.minAge = age
+	}
+}
+
+// WithFreezes holds back every commit while a freeze window is active,
+// except for those of stories with one of the window's allowed labels.
+func WithFreezes(s freeze.Schedule, l Labeler) BumperOption {
+	return func(b *Bumper) {
+		b.freezes = s
+		b.labeler = l
+	}
+}
+
+func WithClock(now func() time.Time) BumperOption {
+	return func(b *Bumper) {
+		b.now = now
+	}
+}
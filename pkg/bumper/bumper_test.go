@@ -2,11 +2,13 @@ package bumper_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
 	"github.com/loggregator/bumper/pkg/git"
 )
 
@@ -149,6 +151,582 @@ var _ = Describe("Bumper", func() {
 		Expect(stc.acceptedRequests).To(ConsistOf(88888888, 55555555, 88888888, 44444444))
 	})
 
+	It("treats a reverted commit and its revert as neutral", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, false, false, true},
+			nameResults:     []string{"", "", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "456789",
+					Subject: "FourthCommit",
+					StoryID: 44444444,
+				},
+				{
+					Hash:    "def123",
+					Subject: "Revert \"SecondCommit\"",
+					StoryID: 55555555,
+					Reverts: "123456",
+				},
+				{
+					Hash:    "123456",
+					Subject: "SecondCommit",
+					StoryID: 55555555,
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		Expect(b.FindBumpSHA()).To(Succeed())
+		Expect(sl.bumpSHA).To(Equal("456789"))
+
+		Expect(sl.commits[1].Reverted).To(BeTrue())
+		Expect(sl.commits[2].Reverted).To(BeTrue())
+		Expect(sl.commits[3].Reverted).To(BeFalse())
+	})
+
+	It("doesn't bump between a reverted commit and its revert", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{false, false, false, true},
+			nameResults:     []string{"", "", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "456789",
+					Subject: "Revert \"SecondCommit\"",
+					StoryID: 55555555,
+					Reverts: "123456",
+				},
+				{
+					Hash:    "def123",
+					Subject: "ThirdCommit",
+					StoryID: 66666666,
+				},
+				{
+					Hash:    "123456",
+					Subject: "SecondCommit",
+					StoryID: 55555555,
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		Expect(b.FindBumpSHA()).To(Succeed())
+		Expect(sl.bumpSHA).To(Equal("789abc"))
+	})
+
+	It("bumps to an accepted commit that is reverted later", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, false, true},
+			nameResults:     []string{"", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "456789",
+					Subject: "Revert \"FirstCommit\"",
+					StoryID: 88888888,
+					Reverts: "789abc",
+				},
+				{
+					Hash:    "def123",
+					Subject: "SecondCommit",
+					StoryID: 55555555,
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		Expect(b.FindBumpSHA()).To(Succeed())
+		Expect(sl.bumpSHA).To(Equal("789abc"))
+	})
+
+	It("blocks a merge commit that brought in unaccepted commits", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, true, false, true},
+			nameResults:     []string{"", "", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "456789",
+					Subject: "ThirdCommit",
+					StoryID: 44444444,
+					Parents: []string{"def123"},
+				},
+				{
+					Hash:    "def123",
+					Subject: "Merge branch 'feature'",
+					Parents: []string{"789abc", "123456"},
+					Merged: []*git.Commit{
+						{
+							Hash:    "123456",
+							Subject: "SideCommit",
+							StoryID: 55555555,
+						},
+					},
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+					Parents: []string{"000000"},
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		Expect(b.FindBumpSHA()).To(Succeed())
+		Expect(sl.bumpSHA).To(Equal("789abc"))
+	})
+
+	It("only bumps to commits whose ancestry is acceptable", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, true, false, true},
+			nameResults:     []string{"", "", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "456789",
+					Subject: "Merge branch 'feature'",
+					Parents: []string{"def123", "123456"},
+				},
+				{
+					Hash:    "def123",
+					Subject: "MainlineCommit",
+					StoryID: 44444444,
+					Parents: []string{"789abc"},
+				},
+				{
+					Hash:    "123456",
+					Subject: "SideCommit",
+					StoryID: 55555555,
+					Parents: []string{"789abc"},
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+					Parents: []string{"000000"},
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		Expect(b.FindBumpSHA()).To(Succeed())
+		Expect(sl.bumpSHA).To(Equal("def123"))
+	})
+
+	It("returns the result of the bump without logging", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{false, true},
+			nameResults:     []string{"Two", "One"},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "123456",
+					Subject: "SecondCommit",
+					StoryID: 55555555,
+				},
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		r, err := b.Bump()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.CommitRange).To(Equal("master..release-elect"))
+		Expect(r.BumpSHA).To(Equal("789abc"))
+		Expect(r.Commits).To(HaveLen(2))
+		Expect(r.Commits[0].StoryName).To(Equal("Two"))
+		Expect(sl.footerCalled).To(BeFalse())
+	})
+
+	It("logs and returns the result of the bump", func() {
+		sl := &spyLogger{}
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true},
+			nameResults:     []string{"One"},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{
+					Hash:    "789abc",
+					Subject: "FirstCommit",
+					StoryID: 88888888,
+				},
+			},
+		}
+
+		b := bumper.New("master..release-elect", sl,
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		r, err := b.FindBump()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.BumpSHA).To(Equal("789abc"))
+		Expect(sl.bumpSHA).To(Equal("789abc"))
+		Expect(sl.footerCalled).To(BeTrue())
+	})
+
+	It("walks back to the newest bumpable commit whose build is green", func() {
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, true, true},
+			nameResults:     []string{"", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "333333", StoryID: 33333333},
+				{Hash: "222222", StoryID: 22222222},
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+		ssc := &spyStatusChecker{
+			green: map[string]bool{"111111": true},
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+			bumper.WithStatusChecker(ssc),
+		)
+		r, err := b.Bump()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.BumpSHA).To(Equal("111111"))
+		Expect(ssc.requests).To(Equal([]string{"333333", "222222", "111111"}))
+		Expect(r.SkippedForCI()).To(Equal(r.Commits[:2]))
+		held, err := r.Hold(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(held.BumpSHA).To(Equal("111111"))
+	})
+
+	It("checks the build again when holding stories", func() {
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, true, true},
+			nameResults:     []string{"", "", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "333333", StoryID: 33333333},
+				{Hash: "222222", StoryID: 22222222},
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+		ssc := &spyStatusChecker{
+			green: map[string]bool{"222222": true},
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+			bumper.WithStatusChecker(ssc),
+		)
+		r, err := b.Bump()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.BumpSHA).To(Equal("222222"))
+
+		held, err := r.Hold(map[int]bool{22222222: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(held.BumpSHA).To(BeEmpty())
+		Expect(ssc.requests).To(Equal([]string{"333333", "222222", "111111"}))
+	})
+
+	It("only checks the build of bumpable commits", func() {
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{false, true},
+			nameResults:     []string{"", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "222222", StoryID: 22222222},
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+		ssc := &spyStatusChecker{
+			green: map[string]bool{"111111": true},
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+			bumper.WithStatusChecker(ssc),
+		)
+		r, err := b.Bump()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.BumpSHA).To(Equal("111111"))
+		Expect(ssc.requests).To(Equal([]string{"111111"}))
+		Expect(r.SkippedForCI()).To(BeEmpty())
+	})
+
+	It("returns an error if checking the build fails", func() {
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true},
+			nameResults:     []string{""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+			bumper.WithStatusChecker(&spyStatusChecker{err: errors.New("ci is down")}),
+		)
+		_, err := b.Bump()
+		Expect(err).To(MatchError("ci is down"))
+	})
+
+	It("returns an error if looking up a story fails", func() {
+		stc := &spyTrackerClient{
+			err: errors.New("failed to get story 11111111: timeout"),
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+		)
+		_, err := b.Bump()
+		Expect(err).To(MatchError("failed to get story 11111111: timeout"))
+	})
+
+	It("treats stories accepted more recently than the minimum age as blocking", func() {
+		now := time.Date(2019, 3, 5, 12, 0, 0, 0, time.UTC)
+		stc := &spyTrackerClient{
+			acceptedResults: []bool{true, true},
+			nameResults:     []string{"", ""},
+		}
+		sgc := &spyGitClient{
+			commitsResult: []*git.Commit{
+				{Hash: "222222", StoryID: 22222222},
+				{Hash: "111111", StoryID: 11111111},
+			},
+		}
+		sat := spyAcceptanceTimer{
+			22222222: now.Add(-time.Hour),
+			11111111: now.Add(-48 * time.Hour),
+		}
+
+		b := bumper.New("master..release-elect", &spyLogger{},
+			bumper.WithGitClient(sgc),
+			bumper.WithTrackerClient(stc),
+			bumper.WithMinAcceptedAge(sat, 24*time.Hour),
+			bumper.WithClock(func() time.Time { return now }),
+		)
+		r, err := b.Bump()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.BumpSHA).To(Equal("111111"))
+		Expect(r.Commits[0].Accepted).To(BeFalse())
+		Expect(r.Commits[0].Soaking).To(BeTrue())
+		Expect(r.Commits[1].Soaking).To(BeFalse())
+		Expect(r.Blockers()).To(Equal(r.Commits[:1]))
+	})
+
+	Context("during a release freeze", func() {
+		var (
+			now      time.Time
+			stc      *spyTrackerClient
+			sgc      *spyGitClient
+			schedule freeze.Schedule
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2019, 12, 24, 12, 0, 0, 0, time.UTC)
+			stc = &spyTrackerClient{
+				acceptedResults: []bool{true, true, true},
+				nameResults:     []string{"", "", ""},
+			}
+			sgc = &spyGitClient{
+				commitsResult: []*git.Commit{
+					{Hash: "333333", StoryID: 33333333},
+					{Hash: "222222", StoryID: 22222222},
+					{Hash: "111111", StoryID: 11111111},
+				},
+			}
+		})
+
+		bump := func(windows ...freeze.Window) bumper.Result {
+			var err error
+			schedule, err = freeze.NewSchedule(windows)
+			Expect(err).ToNot(HaveOccurred())
+
+			b := bumper.New("master..release-elect", &spyLogger{},
+				bumper.WithGitClient(sgc),
+				bumper.WithTrackerClient(stc),
+				bumper.WithFreezes(schedule, spyLabeler{11111111: {"hotfix"}}),
+				bumper.WithClock(func() time.Time { return now }),
+			)
+			r, err := b.Bump()
+			Expect(err).ToNot(HaveOccurred())
+			return r
+		}
+
+		It("does not bump at all", func() {
+			r := bump(freeze.Window{Name: "holidays", Start: "2019-12-20", End: "2020-01-02"})
+
+			Expect(r.BumpSHA).To(BeEmpty())
+			Expect(r.Freeze.Name).To(Equal("holidays"))
+			Expect(r.Commits[2].Frozen).To(BeTrue())
+			Expect(r.Commits[2].Accepted).To(BeFalse())
+		})
+
+		It("only bumps stories with an allowed label", func() {
+			r := bump(freeze.Window{
+				Name:        "holidays",
+				Start:       "2019-12-20",
+				End:         "2020-01-02",
+				AllowLabels: []string{"hotfix"},
+			})
+
+			Expect(r.BumpSHA).To(Equal("111111"))
+			Expect(r.Commits[0].Frozen).To(BeTrue())
+			Expect(r.Commits[2].Frozen).To(BeFalse())
+		})
+
+		It("bumps normally outside of the freeze", func() {
+			r := bump(freeze.Window{Name: "new year", Start: "2020-01-01", End: "2020-01-02"})
+
+			Expect(r.BumpSHA).To(Equal("333333"))
+			Expect(r.Freeze).To(BeNil())
+		})
+	})
+
+	Describe("Result", func() {
+		var r bumper.Result
+
+		BeforeEach(func() {
+			r = bumper.Result{
+				Commits: []*git.Commit{
+					{Hash: "456789", StoryID: 44444444, Accepted: true},
+					{Hash: "def123", StoryID: 88888888, Accepted: true},
+					{Hash: "123456", StoryID: 55555555, Accepted: true},
+					{Hash: "789abc", StoryID: 66666666, Accepted: true},
+				},
+				BumpSHA: "456789",
+			}
+		})
+
+		It("recomputes the bump with held stories", func() {
+			held, err := r.Hold(map[int]bool{55555555: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(held.BumpSHA).To(Equal("789abc"))
+			Expect(r.BumpSHA).To(Equal("456789"))
+		})
+
+		It("returns the commits that are not part of the bump", func() {
+			r.BumpSHA = "123456"
+
+			Expect(r.Remaining()).To(Equal([]*git.Commit{
+				{Hash: "456789", StoryID: 44444444, Accepted: true},
+				{Hash: "def123", StoryID: 88888888, Accepted: true},
+			}))
+		})
+
+		It("returns the commits that are part of the bump", func() {
+			r.BumpSHA = "123456"
+
+			Expect(r.Bumpable()).To(Equal([]*git.Commit{
+				{Hash: "123456", StoryID: 55555555, Accepted: true},
+				{Hash: "789abc", StoryID: 66666666, Accepted: true},
+			}))
+		})
+
+		It("returns the remaining commits that are not accepted", func() {
+			r.Commits[0].Accepted = false
+			r.BumpSHA = "def123"
+
+			Expect(r.Blockers()).To(Equal([]*git.Commit{
+				{Hash: "456789", StoryID: 44444444, Accepted: false},
+			}))
+		})
+
+		It("returns whether all, some or none of the range can be bumped", func() {
+			Expect(r.Status()).To(Equal(bumper.FullBump))
+
+			r.BumpSHA = "123456"
+			Expect(r.Status()).To(Equal(bumper.PartialBump))
+
+			r.BumpSHA = ""
+			Expect(r.Status()).To(Equal(bumper.NothingToBump))
+
+			Expect(bumper.Result{}.Status()).To(Equal(bumper.NothingToBump))
+		})
+
+		It("returns all commits that are not accepted", func() {
+			r.Commits[0].Accepted = false
+			r.Commits[1].Accepted = false
+			r.Commits[1].Reverted = true
+			r.Commits[3].Merged = []*git.Commit{
+				{Hash: "fed321", StoryID: 77777777},
+			}
+
+			Expect(r.Unaccepted()).To(Equal([]*git.Commit{
+				{Hash: "456789", StoryID: 44444444, Accepted: false},
+				{Hash: "fed321", StoryID: 77777777},
+			}))
+		})
+	})
+
 	It("does not log a commit sha if getting commits errors", func() {
 		sgc := &spyGitClient{
 			commitsError: errors.New("an error"),
@@ -181,17 +759,21 @@ type spyTrackerClient struct {
 	acceptedResults  []bool
 	nameCallCount    int
 	nameResults      []string
+	err              error
 }
 
-func (stc *spyTrackerClient) IsAccepted(storyID int) bool {
+func (stc *spyTrackerClient) IsAccepted(storyID int) (bool, error) {
 	stc.acceptedRequests = append(stc.acceptedRequests, storyID)
-	return stc.acceptedResults[len(stc.acceptedRequests)-1]
+	if stc.err != nil {
+		return false, stc.err
+	}
+	return stc.acceptedResults[len(stc.acceptedRequests)-1], nil
 }
 
-func (stc *spyTrackerClient) Name(storyID int) string {
+func (stc *spyTrackerClient) Name(storyID int) (string, error) {
 	stc.nameCallCount++
 
-	return stc.nameResults[stc.nameCallCount-1]
+	return stc.nameResults[stc.nameCallCount-1], nil
 }
 
 type spyLogger struct {
@@ -213,3 +795,26 @@ func (s *spyLogger) Footer(bumpSHA string) {
 	s.footerCalled = true
 	s.bumpSHA = bumpSHA
 }
+
+type spyAcceptanceTimer map[int]time.Time
+
+func (s spyAcceptanceTimer) AcceptedAt(storyID int) (time.Time, error) {
+	return s[storyID], nil
+}
+
+type spyLabeler map[int][]string
+
+func (s spyLabeler) Labels(storyID int) ([]string, error) {
+	return s[storyID], nil
+}
+
+type spyStatusChecker struct {
+	requests []string
+	green    map[string]bool
+	err      error
+}
+
+func (s *spyStatusChecker) Green(sha string) (bool, error) {
+	s.requests = append(s.requests, sha)
+	return s.green[sha], s.err
+}
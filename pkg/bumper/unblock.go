@@ -0,0 +1,131 @@
+package bumper
+
+import (
+	"sort"
+
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+// Unblock is what it takes to bump to the end of the range.
+type Unblock struct {
+	// Stories are the unaccepted stories, sorted by how many commits
+	// accepting them would unlock.
+	Stories []StoryImpact `json:"stories"`
+	// Reachable is false when accepting the stories is not enough to bump to
+	// the end of the range, e.g. because of a freeze or a red build.
+	Reachable bool `json:"reachable"`
+}
+
+// StoryImpact is the effect of accepting a single story.
+type StoryImpact struct {
+	StoryID   int    `json:"story_id"`
+	StoryName string `json:"story_name"`
+	// Required is set when the story has to be accepted to bump to the end
+	// of the range.
+	Required bool `json:"required"`
+	// Unlocks is how many commits accepting only this story would add to the
+	// bump.
+	Unlocks int `json:"unlocks"`
+}
+
+// Required returns the minimal set of stories to accept to bump to the end
+// of the range.
+func (u Unblock) Required() []StoryImpact {
+	var required []StoryImpact
+	for _, s := range u.Stories {
+		if s.Required {
+			required = append(required, s)
+		}
+	}
+	return required
+}
+
+// Unblock computes which stories need to be accepted to bump to the end of
+// the range and how many commits accepting each of them would unlock.
+func (r Result) Unblock() Unblock {
+	if len(r.Commits) == 0 {
+		return Unblock{Reachable: true}
+	}
+
+	commitsAsc := reverse(r.Commits)
+	head := len(commitsAsc) - 1
+	inHead := newAncestry(commitsAsc).of(head)
+	pairs := revertPairs(commitsAsc)
+
+	var ids []int
+	stories := make(map[int]*StoryImpact)
+	for _, c := range commitsAsc {
+		if _, ok := pairs[c.Hash]; ok {
+			continue
+		}
+
+		for _, mc := range c.WithMerged() {
+			if !pending(mc) {
+				continue
+			}
+
+			s, ok := stories[mc.StoryID]
+			if !ok {
+				s = &StoryImpact{StoryID: mc.StoryID, StoryName: mc.StoryName}
+				stories[mc.StoryID] = s
+				ids = append(ids, mc.StoryID)
+			}
+			if inHead[c.Hash] {
+				s.Required = true
+			}
+		}
+	}
+
+	bumped := len(r.Bumpable())
+	var u Unblock
+	for _, id := range ids {
+		s := stories[id]
+		accepted := r.accept(map[int]bool{id: true})
+		s.Unlocks = len(accepted.Bumpable()) - bumped
+		u.Stories = append(u.Stories, *s)
+	}
+
+	sort.SliceStable(u.Stories, func(i, j int) bool {
+		return u.Stories[i].Unlocks > u.Stories[j].Unlocks
+	})
+
+	all := make(map[int]bool)
+	for _, s := range u.Required() {
+		all[s.StoryID] = true
+	}
+	u.Reachable = r.accept(all).BumpSHA == commitsAsc[head].Hash
+
+	return u
+}
+
+// accept returns the result of bumping copies of the commits with the given
+// stories accepted.
+func (r Result) accept(stories map[int]bool) Result {
+	commits := make([]*git.Commit, 0, len(r.Commits))
+	for _, c := range r.Commits {
+		cc := acceptCopy(c, stories)
+		cc.Merged = nil
+		for _, mc := range c.Merged {
+			cc.Merged = append(cc.Merged, acceptCopy(mc, stories))
+		}
+		commits = append(commits, cc)
+	}
+
+	r.Commits = commits
+	r.BumpSHA = findBump(reverse(commits), nil)
+	return r
+}
+
+func acceptCopy(c *git.Commit, stories map[int]bool) *git.Commit {
+	cc := *c
+	if pending(c) && stories[c.StoryID] {
+		cc.Accepted = true
+	}
+	return &cc
+}
+
+// pending reports whether the commit is only blocked by its story not being
+// accepted yet.
+func pending(c *git.Commit) bool {
+	return !c.Accepted && !c.Reverted && !c.Soaking && !c.Frozen && c.StoryID != 0
+}
@@ -0,0 +1,82 @@
+package bumper_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("Unblock", func() {
+	var r bumper.Result
+
+	BeforeEach(func() {
+		r = bumper.Result{
+			CommitRange: "master..release-elect",
+			Commits: []*git.Commit{
+				{Hash: "eeeeee", StoryID: 5, StoryName: "Five"},
+				{Hash: "dddddd", StoryID: 4, StoryName: "Four", Accepted: true},
+				{Hash: "cccccc", StoryID: 3, StoryName: "Three"},
+				{Hash: "bbbbbb", StoryID: 2, StoryName: "Two", Accepted: true},
+				{Hash: "aaaaaa", StoryID: 1, StoryName: "One", Accepted: true},
+			},
+			BumpSHA: "bbbbbb",
+		}
+	})
+
+	It("returns the stories to accept sorted by the commits they unlock", func() {
+		u := r.Unblock()
+
+		Expect(u.Reachable).To(BeTrue())
+		Expect(u.Stories).To(Equal([]bumper.StoryImpact{
+			{StoryID: 3, StoryName: "Three", Required: true, Unlocks: 2},
+			{StoryID: 5, StoryName: "Five", Required: true, Unlocks: 0},
+		}))
+		Expect(u.Required()).To(HaveLen(2))
+	})
+
+	It("does not change the result", func() {
+		r.Unblock()
+
+		Expect(r.Commits[2].Accepted).To(BeFalse())
+		Expect(r.BumpSHA).To(Equal("bbbbbb"))
+	})
+
+	It("counts a story once across commits and merged commits", func() {
+		r.Commits[0] = &git.Commit{
+			Hash:     "eeeeee",
+			Accepted: true,
+			Merged: []*git.Commit{
+				{Hash: "ffffff", StoryID: 3, StoryName: "Three"},
+			},
+		}
+
+		u := r.Unblock()
+
+		Expect(u.Reachable).To(BeTrue())
+		Expect(u.Stories).To(Equal([]bumper.StoryImpact{
+			{StoryID: 3, StoryName: "Three", Required: true, Unlocks: 4},
+		}))
+	})
+
+	It("is not reachable when commits are blocked for other reasons", func() {
+		r.Commits[1].Accepted = false
+		r.Commits[1].Frozen = true
+
+		u := r.Unblock()
+
+		Expect(u.Reachable).To(BeFalse())
+		Expect(u.Stories).To(Equal([]bumper.StoryImpact{
+			{StoryID: 3, StoryName: "Three", Required: true, Unlocks: 1},
+			{StoryID: 5, StoryName: "Five", Required: true, Unlocks: 0},
+		}))
+	})
+
+	It("is reachable without stories when the bump is at the end of the range", func() {
+		u := bumper.Result{}.Unblock()
+
+		Expect(u.Reachable).To(BeTrue())
+		Expect(u.Stories).To(BeEmpty())
+	})
+})
@@ -0,0 +1,13 @@
+package cherrypick_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCherrypick(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cherry-pick Suite")
+}
@@ -0,0 +1,184 @@
+package cherrypick
+
+import (
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+const DefaultBranch = "bumper/cherry-pick"
+
+type GitClient interface {
+	ChangedFiles(sha string) ([]string, error)
+	CherryPick(base, branch string, commits []*git.Commit) (string, error)
+}
+
+// Plan is a list of commits to cherry-pick onto the bump to ship accepted
+// work past the commits blocking it.
+type Plan struct {
+	// Base is the commit the picks are applied onto.
+	Base string
+	// Blocker is the oldest commit holding back the bump.
+	Blocker *git.Commit
+	// Picks are the commits to cherry-pick, oldest first.
+	Picks []*git.Commit
+	// Skipped are the commits after the bump that are not picked.
+	Skipped []*git.Commit
+
+	// Branch and HeadSHA are set once the plan has been verified.
+	Branch  string
+	HeadSHA string
+}
+
+// Planner proposes cherry-picks of accepted commits that don't touch files
+// changed by blocked commits.
+type Planner struct {
+	gc     GitClient
+	branch string
+}
+
+type PlannerOption func(*Planner)
+
+func New(gc GitClient, opts ...PlannerOption) Planner {
+	p := Planner{
+		gc:     gc,
+		branch: DefaultBranch,
+	}
+
+	for _, o := range opts {
+		o(&p)
+	}
+
+	return p
+}
+
+// WithBranch sets the branch the verified plan is written to.
+func WithBranch(branch string) PlannerOption {
+	return func(p *Planner) {
+		p.branch = branch
+	}
+}
+
+// Plan proposes the commits after the bump to cherry-pick. A commit is
+// picked when it is accepted, none of its files are changed by a commit
+// that is not picked before it and none of its stories have commits that
+// are not picked.
+func (p Planner) Plan(r bumper.Result) (Plan, error) {
+	plan := Plan{
+		Base: r.BumpSHA,
+	}
+	if plan.Base == "" {
+		plan.Base = strings.SplitN(r.CommitRange, "..", 2)[0]
+	}
+
+	bumped := make(map[string]bool)
+	for _, c := range r.Bumpable() {
+		bumped[c.Hash] = true
+	}
+
+	var remaining []*git.Commit
+	for i := len(r.Commits) - 1; i >= 0; i-- {
+		c := r.Commits[i]
+		if bumped[c.Hash] {
+			continue
+		}
+		remaining = append(remaining, c)
+
+		if plan.Blocker == nil && !accepted(c) {
+			plan.Blocker = c
+		}
+	}
+
+	if plan.Blocker == nil {
+		return plan, nil
+	}
+
+	files := make(map[string][]string)
+	for _, c := range remaining {
+		f, err := p.gc.ChangedFiles(c.Hash)
+		if err != nil {
+			return Plan{}, err
+		}
+		files[c.Hash] = f
+	}
+
+	// skipping a commit excludes its stories, which may skip commits that
+	// were picked before, so repeat until nothing changes
+	excluded := make(map[int]bool)
+	for {
+		plan.Picks, plan.Skipped = nil, nil
+
+		changed := false
+		blocked := make(map[string]bool)
+		for _, c := range remaining {
+			if pickable(c, excluded) && !touches(files[c.Hash], blocked) {
+				plan.Picks = append(plan.Picks, c)
+				continue
+			}
+
+			plan.Skipped = append(plan.Skipped, c)
+			for _, f := range files[c.Hash] {
+				blocked[f] = true
+			}
+			for _, mc := range c.WithMerged() {
+				if mc.StoryID != 0 && !excluded[mc.StoryID] {
+					excluded[mc.StoryID] = true
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			return plan, nil
+		}
+	}
+}
+
+// Verify cherry-picks the plan in a temporary worktree and points the
+// branch at the result.
+func (p Planner) Verify(plan Plan) (Plan, error) {
+	head, err := p.gc.CherryPick(plan.Base, p.branch, plan.Picks)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan.Branch = p.branch
+	plan.HeadSHA = head
+	return plan, nil
+}
+
+func accepted(c *git.Commit) bool {
+	if c.Reverted {
+		return true
+	}
+
+	for _, mc := range c.WithMerged() {
+		if !mc.Accepted {
+			return false
+		}
+	}
+	return true
+}
+
+func pickable(c *git.Commit, excluded map[int]bool) bool {
+	if c.Reverted {
+		return false
+	}
+
+	for _, mc := range c.WithMerged() {
+		if !mc.Accepted || excluded[mc.StoryID] {
+			return false
+		}
+	}
+	return true
+}
+
+func touches(files []string, blocked map[string]bool) bool {
+	for _, f := range files {
+		if blocked[f] {
+			return true
+		}
+	}
+	return false
+}
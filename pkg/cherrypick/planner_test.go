@@ -0,0 +1,141 @@
+package cherrypick_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/cherrypick"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("Planner", func() {
+	var (
+		sgc *spyGitClient
+		r   bumper.Result
+	)
+
+	BeforeEach(func() {
+		sgc = &spyGitClient{
+			files: map[string][]string{
+				"ffffff": {"src/sink.go"},
+				"eeeeee": {"src/drain.go"},
+				"dddddd": {"src/sink.go", "README.md"},
+				"cccccc": {"src/sink.go"},
+			},
+			head: "999999",
+		}
+		r = bumper.Result{
+			CommitRange: "master..release-elect",
+			Commits: []*git.Commit{
+				{Hash: "ffffff", StoryID: 6, Accepted: true},
+				{Hash: "eeeeee", StoryID: 5, Accepted: true},
+				{Hash: "dddddd", StoryID: 4, Accepted: true},
+				{Hash: "cccccc", StoryID: 3},
+				{Hash: "bbbbbb", StoryID: 2, Accepted: true},
+				{Hash: "aaaaaa", StoryID: 1, Accepted: true},
+			},
+			BumpSHA: "bbbbbb",
+		}
+	})
+
+	It("picks accepted commits that don't touch files of blocked commits", func() {
+		plan, err := cherrypick.New(sgc).Plan(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(plan.Base).To(Equal("bbbbbb"))
+		Expect(plan.Blocker).To(Equal(r.Commits[3]))
+		Expect(plan.Picks).To(Equal([]*git.Commit{r.Commits[1]}))
+		Expect(plan.Skipped).To(Equal([]*git.Commit{r.Commits[3], r.Commits[2], r.Commits[0]}))
+	})
+
+	It("does not split stories across the picks", func() {
+		r.Commits[0].StoryID = 5
+
+		plan, err := cherrypick.New(sgc).Plan(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(plan.Picks).To(BeEmpty())
+	})
+
+	It("picks onto the start of the range when nothing is bumped", func() {
+		r.Commits[5].Accepted = false
+		r.BumpSHA = ""
+		sgc.files["bbbbbb"] = []string{"src/other.go"}
+		sgc.files["aaaaaa"] = []string{"src/first.go"}
+
+		plan, err := cherrypick.New(sgc).Plan(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(plan.Base).To(Equal("master"))
+		Expect(plan.Blocker).To(Equal(r.Commits[5]))
+		Expect(plan.Picks).To(Equal([]*git.Commit{r.Commits[4], r.Commits[1]}))
+	})
+
+	It("has nothing to pick when nothing is blocked", func() {
+		r.Commits[3].Accepted = true
+		r.BumpSHA = "ffffff"
+
+		plan, err := cherrypick.New(sgc).Plan(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(plan.Blocker).To(BeNil())
+		Expect(plan.Picks).To(BeEmpty())
+		Expect(sgc.changedFilesRequests).To(BeEmpty())
+	})
+
+	It("returns an error if getting the changed files fails", func() {
+		sgc.changedFilesErr = errors.New("bad revision")
+
+		_, err := cherrypick.New(sgc).Plan(r)
+		Expect(err).To(MatchError("bad revision"))
+	})
+
+	It("verifies the plan on the branch", func() {
+		p := cherrypick.New(sgc, cherrypick.WithBranch("bump-around"))
+		plan, err := p.Plan(r)
+		Expect(err).ToNot(HaveOccurred())
+
+		plan, err = p.Verify(plan)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(sgc.cherryPickBase).To(Equal("bbbbbb"))
+		Expect(sgc.cherryPickBranch).To(Equal("bump-around"))
+		Expect(sgc.cherryPickCommits).To(Equal(plan.Picks))
+		Expect(plan.Branch).To(Equal("bump-around"))
+		Expect(plan.HeadSHA).To(Equal("999999"))
+	})
+
+	It("returns an error if the plan does not apply cleanly", func() {
+		sgc.cherryPickErr = errors.New("eeeeee does not apply cleanly")
+
+		_, err := cherrypick.New(sgc).Verify(cherrypick.Plan{})
+		Expect(err).To(MatchError("eeeeee does not apply cleanly"))
+	})
+})
+
+type spyGitClient struct {
+	files                map[string][]string
+	changedFilesRequests []string
+	changedFilesErr      error
+
+	head              string
+	cherryPickBase    string
+	cherryPickBranch  string
+	cherryPickCommits []*git.Commit
+	cherryPickErr     error
+}
+
+func (s *spyGitClient) ChangedFiles(sha string) ([]string, error) {
+	s.changedFilesRequests = append(s.changedFilesRequests, sha)
+	return s.files[sha], s.changedFilesErr
+}
+
+func (s *spyGitClient) CherryPick(base, branch string, commits []*git.Commit) (string, error) {
+	s.cherryPickBase = base
+	s.cherryPickBranch = branch
+	s.cherryPickCommits = commits
+	return s.head, s.cherryPickErr
+}
@@ -0,0 +1,13 @@
+package ci_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCi(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CI Suite")
+}
@@ -0,0 +1,71 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type HTTPClient interface {
+	Get(url string) (*http.Response, error)
+}
+
+// HTTPChecker checks the build of a commit against a generic HTTP endpoint.
+// The endpoint is expected to respond with JSON like {"state": "success"}.
+type HTTPChecker struct {
+	urlTemplate string
+	httpClient  HTTPClient
+}
+
+type HTTPCheckerOption func(*HTTPChecker)
+
+// NewHTTPChecker creates a checker that requests urlTemplate with {sha}
+// replaced by the commit sha.
+func NewHTTPChecker(urlTemplate string, opts ...HTTPCheckerOption) *HTTPChecker {
+	c := &HTTPChecker{
+		urlTemplate: urlTemplate,
+		httpClient:  http.DefaultClient,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+func WithHTTPClient(httpClient HTTPClient) HTTPCheckerOption {
+	return func(c *HTTPChecker) {
+		c.httpClient = httpClient
+	}
+}
+
+// Green reports whether the endpoint responds with a success state. A
+// commit the endpoint does not know about is not green.
+func (c *HTTPChecker) Green(sha string) (bool, error) {
+	url := strings.ReplaceAll(c.urlTemplate, "{sha}", sha)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	var status struct {
+		State string `json:"state"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	if err != nil {
+		return false, err
+	}
+
+	return status.State == "success", nil
+}
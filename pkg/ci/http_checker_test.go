@@ -0,0 +1,81 @@
+package ci_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/ci"
+)
+
+var _ = Describe("HTTPChecker", func() {
+	var (
+		client  *spyHTTPClient
+		checker *ci.HTTPChecker
+	)
+
+	BeforeEach(func() {
+		client = &spyHTTPClient{status: http.StatusOK}
+		checker = ci.NewHTTPChecker(
+			"https://ci.example.com/builds/{sha}",
+			ci.WithHTTPClient(client),
+		)
+	})
+
+	It("requests the status of the sha", func() {
+		client.body = `{"state": "success"}`
+
+		Expect(checker.Green("abc123")).To(BeTrue())
+		Expect(client.url).To(Equal("https://ci.example.com/builds/abc123"))
+	})
+
+	It("is not green when the state is not success", func() {
+		client.body = `{"state": "failure"}`
+
+		Expect(checker.Green("abc123")).To(BeFalse())
+	})
+
+	It("is not green when the endpoint does not know the sha", func() {
+		client.status = http.StatusNotFound
+
+		Expect(checker.Green("abc123")).To(BeFalse())
+	})
+
+	It("returns an error when the endpoint fails", func() {
+		client.status = http.StatusInternalServerError
+
+		_, err := checker.Green("abc123")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error when the request fails", func() {
+		client.err = errors.New("connection refused")
+
+		_, err := checker.Green("abc123")
+		Expect(err).To(MatchError("connection refused"))
+	})
+})
+
+type spyHTTPClient struct {
+	url    string
+	status int
+	body   string
+	err    error
+}
+
+func (s *spyHTTPClient) Get(url string) (*http.Response, error) {
+	s.url = url
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &http.Response{
+		StatusCode: s.status,
+		Status:     http.StatusText(s.status),
+		Body:       ioutil.NopCloser(strings.NewReader(s.body)),
+	}, nil
+}
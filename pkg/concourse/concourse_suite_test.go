@@ -0,0 +1,13 @@
+package concourse_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestConcourse(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Concourse Suite")
+}
@@ -0,0 +1,92 @@
+package concourse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+// GitRepos clones the source repository with git.
+type GitRepos struct {
+	exec git.CommandExecutor
+}
+
+func NewGitRepos(exec git.CommandExecutor) GitRepos {
+	return GitRepos{
+		exec: exec,
+	}
+}
+
+func (r GitRepos) Open(src Source, dir string) (GitClient, error) {
+	var opts []git.ClientOption
+	c := &repoClient{}
+	if src.PrivateKey != "" {
+		var err error
+		c.keyPath, err = writeKey(src.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, git.WithEnv(
+			"GIT_SSH_COMMAND=ssh -i "+c.keyPath+" -o StrictHostKeyChecking=no",
+		))
+	}
+
+	c.GitClient = git.NewClient(append([]git.ClientOption{
+		git.WithCommandExecutor(r.exec),
+		git.WithRepoPath(dir),
+		git.WithFollowBumpsOf(src.FollowBumpsOf...),
+		git.WithFirstParent(),
+	}, opts...)...)
+
+	var err error
+	_, statErr := os.Stat(filepath.Join(dir, ".git"))
+	if statErr == nil {
+		err = c.Fetch("origin")
+	} else {
+		err = git.NewClient(append([]git.ClientOption{
+			git.WithCommandExecutor(r.exec),
+		}, opts...)...).Clone(src.URI, dir)
+	}
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// repoClient is a clone that removes its private key when closed.
+type repoClient struct {
+	git.GitClient
+	keyPath string
+}
+
+func (c *repoClient) Close() error {
+	if c.keyPath == "" {
+		return nil
+	}
+	return os.Remove(c.keyPath)
+}
+
+// writeKey writes the private key to a file only readable by the user.
+func writeKey(privateKey string) (string, error) {
+	f, err := ioutil.TempFile("", "bumper-resource-key")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	err = f.Chmod(0600)
+	if err == nil {
+		_, err = f.WriteString(privateKey)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
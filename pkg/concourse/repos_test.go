@@ -0,0 +1,71 @@
+package concourse_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/concourse"
+)
+
+var _ = Describe("GitRepos", func() {
+	var (
+		se  *spyCommandExecutor
+		dir string
+	)
+
+	BeforeEach(func() {
+		se = &spyCommandExecutor{}
+
+		var err error
+		dir, err = ioutil.TempDir("", "bumper-repos")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("uses the private key for the git commands of the clone only", func() {
+		gc, err := concourse.NewGitRepos(se).Open(concourse.Source{
+			URI:        "git@example.com:release.git",
+			PrivateKey: "secret",
+		}, dir)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.commands).To(HaveLen(1))
+		keyPath := sshKeyPath(se.commands[0].Env)
+		Expect(ioutil.ReadFile(keyPath)).To(Equal([]byte("secret")))
+		Expect(os.Getenv("GIT_SSH_COMMAND")).To(BeEmpty())
+
+		Expect(gc.Push("origin", "abc123", "master")).To(Succeed())
+		Expect(sshKeyPath(se.commands[1].Env)).To(Equal(keyPath))
+
+		Expect(gc.Close()).To(Succeed())
+		_, err = os.Stat(keyPath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+})
+
+// sshKeyPath returns the key given to ssh by GIT_SSH_COMMAND in env.
+func sshKeyPath(env []string) string {
+	for _, e := range env {
+		if strings.HasPrefix(e, "GIT_SSH_COMMAND=") {
+			return strings.Fields(e)[2]
+		}
+	}
+	return ""
+}
+
+type spyCommandExecutor struct {
+	commands []*exec.Cmd
+}
+
+func (s *spyCommandExecutor) Run(cmd *exec.Cmd) error {
+	s.commands = append(s.commands, cmd)
+	return nil
+}
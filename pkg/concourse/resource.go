@@ -0,0 +1,269 @@
+package concourse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/logger"
+	"github.com/loggregator/bumper/pkg/tracker"
+)
+
+const (
+	defaultCommitRange  = "origin/master..origin/release-elect"
+	defaultTargetBranch = "master"
+)
+
+// Source is the configuration of the resource.
+type Source struct {
+	URI             string   `json:"uri"`
+	PrivateKey      string   `json:"private_key"`
+	CommitRange     string   `json:"commit_range"`
+	TargetBranch    string   `json:"target_branch"`
+	FollowBumpsOf   []string `json:"follow_bumps_of"`
+	TrackerAPIToken string   `json:"tracker_api_token"`
+}
+
+func (s Source) commitRange() string {
+	if s.CommitRange == "" {
+		return defaultCommitRange
+	}
+	return s.CommitRange
+}
+
+func (s Source) targetBranch() string {
+	if s.TargetBranch == "" {
+		return defaultTargetBranch
+	}
+	return s.TargetBranch
+}
+
+// Version is a bump SHA.
+type Version struct {
+	SHA string `json:"sha"`
+}
+
+type MetadataField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CheckRequest struct {
+	Source  Source   `json:"source"`
+	Version *Version `json:"version"`
+}
+
+type InRequest struct {
+	Source  Source  `json:"source"`
+	Version Version `json:"version"`
+}
+
+type OutParams struct {
+	// SHAFile is the path, relative to the sources directory, of a file
+	// containing the SHA to push. The bump is computed when it is empty.
+	SHAFile string `json:"sha_file"`
+}
+
+type OutRequest struct {
+	Source Source    `json:"source"`
+	Params OutParams `json:"params"`
+}
+
+// Response is the output of in and out.
+type Response struct {
+	Version  Version         `json:"version"`
+	Metadata []MetadataField `json:"metadata"`
+}
+
+// GitClient is a client for a clone of the source repository.
+type GitClient interface {
+	bumper.GitClient
+	Push(remote, sha, branch string) error
+	// Close removes the credentials written for the clone.
+	Close() error
+}
+
+// Repos provides clones of the source repository.
+type Repos interface {
+	// Open clones the source repository into dir, or fetches if dir
+	// already contains a clone.
+	Open(src Source, dir string) (GitClient, error)
+}
+
+// Resource implements check, in and out of a Concourse resource whose
+// versions are bump SHAs.
+type Resource struct {
+	repos Repos
+	tc    bumper.TrackerClient
+}
+
+func New(repos Repos, tc bumper.TrackerClient) Resource {
+	return Resource{
+		repos: repos,
+		tc:    tc,
+	}
+}
+
+// NewFromSource returns a Resource that clones with git and looks up
+// stories with the source's Tracker API token.
+func NewFromSource(src Source) Resource {
+	var httpClient tracker.HTTPClient = http.DefaultClient
+	if src.TrackerAPIToken != "" {
+		httpClient = tracker.NewAPIHTTPClient(http.DefaultClient, src.TrackerAPIToken)
+	}
+
+	return New(
+		NewGitRepos(git.ExecCommandExecutor{}),
+		tracker.NewClient(tracker.WithHTTPClient(httpClient)),
+	)
+}
+
+// Check computes the bump in a clone kept in dir. It emits the bump SHA so a
+// new version appears whenever it advances.
+func (r Resource) Check(req CheckRequest, dir string) ([]Version, error) {
+	gc, err := r.repos.Open(req.Source, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer gc.Close()
+
+	res, err := r.bump(req.Source.commitRange(), gc)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.BumpSHA == "" {
+		if req.Version != nil {
+			return []Version{*req.Version}, nil
+		}
+		return []Version{}, nil
+	}
+
+	return []Version{{SHA: res.BumpSHA}}, nil
+}
+
+// In clones the repository into dir/repo and writes the SHA, the commits
+// from the target branch up to the SHA as JSON and a report of the commit
+// range into dir.
+func (r Resource) In(req InRequest, dir string) (Response, error) {
+	gc, err := r.repos.Open(req.Source, filepath.Join(dir, "repo"))
+	if err != nil {
+		return Response{}, err
+	}
+	defer gc.Close()
+
+	res, err := r.bump(req.Source.commitRange(), gc)
+	if err != nil {
+		return Response{}, err
+	}
+
+	// The version may be older than the commits now in the range, so its
+	// commits are those it adds to the target branch.
+	version, err := r.bump("origin/"+req.Source.targetBranch()+".."+req.Version.SHA, gc)
+	if err != nil {
+		return Response{}, err
+	}
+	version.BumpSHA = req.Version.SHA
+
+	err = ioutil.WriteFile(filepath.Join(dir, "sha"), []byte(req.Version.SHA+"\n"), 0644)
+	if err != nil {
+		return Response{}, err
+	}
+
+	commits := version.Bumpable()
+	if commits == nil {
+		commits = []*git.Commit{}
+	}
+	commitsJSON, err := json.MarshalIndent(commits, "", "  ")
+	if err != nil {
+		return Response{}, err
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "commits.json"), commitsJSON, 0644)
+	if err != nil {
+		return Response{}, err
+	}
+
+	report, err := os.Create(filepath.Join(dir, "report.txt"))
+	if err != nil {
+		return Response{}, err
+	}
+	defer report.Close()
+
+	vl := logger.NewVerboseLogger(
+		logger.WithVerboseWriter(report),
+		logger.WithColorDisabled(),
+	)
+	vl.Header(res.CommitRange)
+	for _, c := range res.Commits {
+		vl.Commit(c)
+	}
+	vl.Footer(res.BumpSHA)
+
+	return Response{
+		Version:  req.Version,
+		Metadata: metadata(res, commits),
+	}, nil
+}
+
+// Out fast-forwards the target branch to the SHA in the sha file, or to the
+// computed bump if no sha file is given. The clone is made in workDir.
+func (r Resource) Out(req OutRequest, sourcesDir, workDir string) (Response, error) {
+	gc, err := r.repos.Open(req.Source, workDir)
+	if err != nil {
+		return Response{}, err
+	}
+	defer gc.Close()
+
+	var sha string
+	if req.Params.SHAFile != "" {
+		b, err := ioutil.ReadFile(filepath.Join(sourcesDir, req.Params.SHAFile))
+		if err != nil {
+			return Response{}, err
+		}
+		sha = strings.TrimSpace(string(b))
+	} else {
+		res, err := r.bump(req.Source.commitRange(), gc)
+		if err != nil {
+			return Response{}, err
+		}
+		sha = res.BumpSHA
+	}
+
+	if sha == "" {
+		return Response{}, errors.New("there are no commits to bump")
+	}
+
+	err = gc.Push("origin", sha, req.Source.targetBranch())
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Version: Version{SHA: sha},
+		Metadata: []MetadataField{
+			{Name: "target_branch", Value: req.Source.targetBranch()},
+		},
+	}, nil
+}
+
+func (r Resource) bump(commitRange string, gc GitClient) (bumper.Result, error) {
+	return bumper.New(commitRange, logger.NewLogger(),
+		bumper.WithGitClient(gc),
+		bumper.WithTrackerClient(r.tc),
+	).Bump()
+}
+
+func metadata(res bumper.Result, commits []*git.Commit) []MetadataField {
+	return []MetadataField{
+		{Name: "commit_range", Value: res.CommitRange},
+		{Name: "commits", Value: fmt.Sprint(len(commits))},
+		{Name: "blockers", Value: fmt.Sprint(len(res.Blockers()))},
+	}
+}
@@ -0,0 +1,245 @@
+package concourse_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/concourse"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("Resource", func() {
+	var (
+		sgc *spyGitClient
+		sr  *spyRepos
+		r   concourse.Resource
+		dir string
+		src concourse.Source
+	)
+
+	BeforeEach(func() {
+		sgc = &spyGitClient{
+			commits: []*git.Commit{
+				{Hash: "def123", Subject: "Second", StoryID: 2},
+				{Hash: "789abc", Subject: "First", StoryID: 1},
+			},
+		}
+		sr = &spyRepos{gc: sgc}
+		r = concourse.New(sr, stubTrackerClient{accepted: map[int]bool{1: true}})
+		src = concourse.Source{URI: "git@example.com:release.git"}
+
+		var err error
+		dir, err = ioutil.TempDir("", "bumper-resource")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	Describe("Check", func() {
+		It("emits the bump SHA", func() {
+			versions, err := r.Check(concourse.CheckRequest{Source: src}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versions).To(Equal([]concourse.Version{{SHA: "789abc"}}))
+			Expect(sr.dirs).To(Equal([]string{dir}))
+			Expect(sgc.commitRanges).To(Equal([]string{"origin/master..origin/release-elect"}))
+			Expect(sgc.closed).To(BeTrue())
+		})
+
+		It("uses the configured commit range", func() {
+			src.CommitRange = "origin/main..origin/release-elect"
+
+			_, err := r.Check(concourse.CheckRequest{Source: src}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(sgc.commitRanges).To(Equal([]string{"origin/main..origin/release-elect"}))
+		})
+
+		It("emits the current version when there is nothing to bump", func() {
+			sgc.commits = nil
+
+			versions, err := r.Check(concourse.CheckRequest{
+				Source:  src,
+				Version: &concourse.Version{SHA: "fed321"},
+			}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versions).To(Equal([]concourse.Version{{SHA: "fed321"}}))
+		})
+
+		It("emits no versions when there is nothing to bump yet", func() {
+			sgc.commits = nil
+
+			versions, err := r.Check(concourse.CheckRequest{Source: src}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(versions).To(BeEmpty())
+		})
+
+		It("returns an error if the repository can't be opened", func() {
+			sr.err = errors.New("could not clone")
+
+			_, err := r.Check(concourse.CheckRequest{Source: src}, dir)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("In", func() {
+		It("writes the SHA, commits and report", func() {
+			resp, err := r.In(concourse.InRequest{
+				Source:  src,
+				Version: concourse.Version{SHA: "789abc"},
+			}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(sr.dirs).To(Equal([]string{filepath.Join(dir, "repo")}))
+			Expect(sgc.commitRanges).To(Equal([]string{
+				"origin/master..origin/release-elect",
+				"origin/master..789abc",
+			}))
+			Expect(sgc.closed).To(BeTrue())
+			Expect(resp.Version).To(Equal(concourse.Version{SHA: "789abc"}))
+			Expect(resp.Metadata).To(ContainElement(concourse.MetadataField{Name: "commits", Value: "1"}))
+			Expect(resp.Metadata).To(ContainElement(concourse.MetadataField{Name: "blockers", Value: "1"}))
+
+			sha, err := ioutil.ReadFile(filepath.Join(dir, "sha"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(sha)).To(Equal("789abc\n"))
+
+			commits, err := ioutil.ReadFile(filepath.Join(dir, "commits.json"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commits).To(MatchJSON(`[
+				{"sha": "789abc", "subject": "First", "story_id": 1, "story_name": "", "accepted": true}
+			]`))
+
+			report, err := ioutil.ReadFile(filepath.Join(dir, "report.txt"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(report)).To(ContainSubstring("This is the commit you should bump to: 789abc"))
+		})
+
+		It("writes the commits the version adds to the target branch", func() {
+			sgc.rangeCommits = map[string][]*git.Commit{
+				"origin/main..fed321": {
+					{Hash: "fed321", Subject: "Old", StoryID: 3},
+				},
+			}
+			src.TargetBranch = "main"
+
+			resp, err := r.In(concourse.InRequest{
+				Source:  src,
+				Version: concourse.Version{SHA: "fed321"},
+			}, dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(resp.Metadata).To(ContainElement(concourse.MetadataField{Name: "commits", Value: "1"}))
+
+			commits, err := ioutil.ReadFile(filepath.Join(dir, "commits.json"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(commits).To(MatchJSON(`[
+				{"sha": "fed321", "subject": "Old", "story_id": 3, "story_name": "", "accepted": false}
+			]`))
+		})
+	})
+
+	Describe("Out", func() {
+		It("pushes the SHA from the sha file to the target branch", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "sha"), []byte("789abc\n"), 0644)).To(Succeed())
+			src.TargetBranch = "main"
+
+			resp, err := r.Out(concourse.OutRequest{
+				Source: src,
+				Params: concourse.OutParams{SHAFile: "sha"},
+			}, dir, "/work")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(sr.dirs).To(Equal([]string{"/work"}))
+			Expect(sgc.pushes).To(Equal([]string{"origin 789abc main"}))
+			Expect(resp.Version).To(Equal(concourse.Version{SHA: "789abc"}))
+		})
+
+		It("computes the bump when no sha file is given", func() {
+			_, err := r.Out(concourse.OutRequest{Source: src}, dir, "/work")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(sgc.pushes).To(Equal([]string{"origin 789abc master"}))
+		})
+
+		It("returns an error when there is nothing to bump", func() {
+			sgc.commits = nil
+
+			_, err := r.Out(concourse.OutRequest{Source: src}, dir, "/work")
+			Expect(err).To(HaveOccurred())
+			Expect(sgc.pushes).To(BeEmpty())
+		})
+
+		It("returns an error if the push fails", func() {
+			sgc.pushErr = errors.New("non-fast-forward")
+
+			_, err := r.Out(concourse.OutRequest{Source: src}, dir, "/work")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+type spyRepos struct {
+	dirs []string
+	gc   *spyGitClient
+	err  error
+}
+
+func (s *spyRepos) Open(src concourse.Source, dir string) (concourse.GitClient, error) {
+	s.dirs = append(s.dirs, dir)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.gc, nil
+}
+
+type spyGitClient struct {
+	commitRanges []string
+	commits      []*git.Commit
+	rangeCommits map[string][]*git.Commit
+	pushes       []string
+	pushErr      error
+	closed       bool
+}
+
+func (s *spyGitClient) Commits(commitRange string) ([]*git.Commit, error) {
+	s.commitRanges = append(s.commitRanges, commitRange)
+	if commits, ok := s.rangeCommits[commitRange]; ok {
+		return commits, nil
+	}
+	return s.commits, nil
+}
+
+func (s *spyGitClient) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *spyGitClient) Push(remote, sha, branch string) error {
+	if s.pushErr != nil {
+		return s.pushErr
+	}
+	s.pushes = append(s.pushes, remote+" "+sha+" "+branch)
+	return nil
+}
+
+type stubTrackerClient struct {
+	accepted map[int]bool
+}
+
+func (s stubTrackerClient) IsAccepted(storyID int) (bool, error) {
+	return s.accepted[storyID], nil
+}
+
+func (s stubTrackerClient) Name(storyID int) (string, error) {
+	return "", nil
+}
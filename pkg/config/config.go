@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/freeze"
+)
+
+// Config is the bumper configuration file.
+type Config struct {
+	// CommitRange is the commit range bumped when no repos are listed. It
+	// is detected from the branches of the repository when empty.
+	CommitRange string `json:"commit_range"`
+	Repos       []Repo `json:"repos"`
+	Notify      Notify `json:"notify"`
+	// Freezes are the release freeze windows during which bumps are held
+	// back.
+	Freezes []freeze.Window `json:"freezes"`
+	// SubmoduleMirrors maps submodule URLs to bare mirrors the followed
+	// submodules are read from instead of their checkouts.
+	SubmoduleMirrors map[string]string `json:"submodule_mirrors"`
+}
+
+// Repo configures how a single repository is bumped.
+type Repo struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// CommitRange is detected from the branches of the repository when
+	// empty.
+	CommitRange   string   `json:"commit_range"`
+	FollowBumpsOf []string `json:"follow_bumps_of"`
+	// GitHubRepo is the GitHub repository (owner/name) whose commit
+	// statuses are checked.
+	GitHubRepo string `json:"github_repo"`
+}
+
+// Notify configures where notifications about bumps are sent.
+type Notify struct {
+	SlackWebhookURL string `json:"slack_webhook_url"`
+	WebhookURL      string `json:"webhook_url"`
+	Email           *Email `json:"email"`
+
+	// BlockedAfter is how long a story may block the bump before it is
+	// announced.
+	BlockedAfter Duration `json:"blocked_after"`
+	// StateFile keeps what has been announced between runs.
+	StateFile string `json:"state_file"`
+	// Templates override the message templates by notification kind.
+	Templates map[string]string `json:"templates"`
+}
+
+// Email configures sending notifications over SMTP.
+type Email struct {
+	Addr     string   `json:"addr"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+}
+
+// Duration is a time.Duration written as a string such as "72h".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// Load reads the configuration file at path and fills in defaults.
+func Load(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+
+	var c Config
+	err = json.NewDecoder(f).Decode(&c)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to parse config %s: %s", path, err)
+	}
+
+	for i := range c.Repos {
+		r := &c.Repos[i]
+		if r.Path == "" {
+			return Config{}, fmt.Errorf("repo %d in config %s has no path", i, path)
+		}
+		if r.Name == "" {
+			r.Name = filepath.Base(r.Path)
+		}
+	}
+
+	_, err = freeze.NewSchedule(c.Freezes)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %s", path, err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,182 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/config"
+	"github.com/loggregator/bumper/pkg/freeze"
+)
+
+var _ = Describe("Config", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "bumper-config")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeConfig := func(content string) string {
+		path := filepath.Join(dir, "config.json")
+		Expect(ioutil.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+
+	It("loads repos and fills in defaults", func() {
+		path := writeConfig(`{
+			"repos": [
+				{
+					"path": "/repos/loggregator-release",
+					"follow_bumps_of": ["src/loggregator"]
+				},
+				{
+					"name": "agent",
+					"path": "/repos/loggregator-agent-release",
+					"commit_range": "main..release-elect",
+					"github_repo": "cloudfoundry/loggregator-agent-release"
+				}
+			]
+		}`)
+
+		c, err := config.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Repos).To(Equal([]config.Repo{
+			{
+				Name:          "loggregator-release",
+				Path:          "/repos/loggregator-release",
+				FollowBumpsOf: []string{"src/loggregator"},
+			},
+			{
+				Name:        "agent",
+				Path:        "/repos/loggregator-agent-release",
+				CommitRange: "main..release-elect",
+				GitHubRepo:  "cloudfoundry/loggregator-agent-release",
+			},
+		}))
+	})
+
+	It("loads the commit range", func() {
+		path := writeConfig(`{"commit_range": "main..release-candidate"}`)
+
+		c, err := config.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.CommitRange).To(Equal("main..release-candidate"))
+	})
+
+	It("loads the submodule mirrors", func() {
+		path := writeConfig(`{
+			"submodule_mirrors": {
+				"https://github.com/cloudfoundry/loggregator": "/mirrors/loggregator.git"
+			}
+		}`)
+
+		c, err := config.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.SubmoduleMirrors).To(Equal(map[string]string{
+			"https://github.com/cloudfoundry/loggregator": "/mirrors/loggregator.git",
+		}))
+	})
+
+	It("loads notification settings", func() {
+		path := writeConfig(`{
+			"notify": {
+				"slack_webhook_url": "https://hooks.slack.com/services/T/B/X",
+				"email": {
+					"addr": "smtp.example.com:587",
+					"from": "bumper@example.com",
+					"to": ["team@example.com"]
+				},
+				"blocked_after": "72h",
+				"state_file": "/var/lib/bumper/notify.json",
+				"templates": {"bump": "bump {{.BumpSHA}}"}
+			}
+		}`)
+
+		c, err := config.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Notify).To(Equal(config.Notify{
+			SlackWebhookURL: "https://hooks.slack.com/services/T/B/X",
+			Email: &config.Email{
+				Addr: "smtp.example.com:587",
+				From: "bumper@example.com",
+				To:   []string{"team@example.com"},
+			},
+			BlockedAfter: config.Duration(72 * time.Hour),
+			StateFile:    "/var/lib/bumper/notify.json",
+			Templates:    map[string]string{"bump": "bump {{.BumpSHA}}"},
+		}))
+	})
+
+	It("loads release freezes", func() {
+		path := writeConfig(`{
+			"freezes": [
+				{
+					"name": "holidays",
+					"start": "2019-12-20",
+					"end": "2020-01-02",
+					"allow_labels": ["hotfix"]
+				},
+				{
+					"name": "weekends",
+					"weekdays": ["saturday", "sunday"],
+					"timezone": "America/Denver"
+				}
+			]
+		}`)
+
+		c, err := config.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(c.Freezes).To(Equal([]freeze.Window{
+			{Name: "holidays", Start: "2019-12-20", End: "2020-01-02", AllowLabels: []string{"hotfix"}},
+			{Name: "weekends", Weekdays: []string{"saturday", "sunday"}, Timezone: "America/Denver"},
+		}))
+	})
+
+	It("returns an error for invalid freezes", func() {
+		path := writeConfig(`{"freezes": [{"name": "never"}]}`)
+
+		_, err := config.Load(path)
+		Expect(err).To(MatchError(ContainSubstring(`freeze "never"`)))
+	})
+
+	It("returns an error for invalid durations", func() {
+		path := writeConfig(`{"notify": {"blocked_after": "soon"}}`)
+
+		_, err := config.Load(path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error if a repo has no path", func() {
+		path := writeConfig(`{"repos": [{"name": "no-path"}]}`)
+
+		_, err := config.Load(path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error for invalid JSON", func() {
+		path := writeConfig(`{`)
+
+		_, err := config.Load(path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error if the file does not exist", func() {
+		_, err := config.Load(filepath.Join(dir, "missing.json"))
+		Expect(err).To(HaveOccurred())
+	})
+})
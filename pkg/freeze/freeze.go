@@ -0,0 +1,193 @@
+package freeze
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+)
+
+// Window is a period during which bumps are frozen. A window is active when
+// all of its date range, weekdays and times of day match.
+type Window struct {
+	Name string `json:"name"`
+
+	// Start and End are the first and last day of the freeze, e.g.
+	// "2019-12-20".
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Weekdays the freeze applies to, e.g. "saturday".
+	Weekdays []string `json:"weekdays"`
+	// From and Until are the times of day the freeze applies between, e.g.
+	// "17:00" and "09:00". Until is exclusive and may be before From to span
+	// midnight.
+	From  string `json:"from"`
+	Until string `json:"until"`
+	// Timezone the window is evaluated in. Defaults to UTC.
+	Timezone string `json:"timezone"`
+
+	// AllowLabels are the labels of stories that may still be bumped during
+	// the freeze. Without labels nothing is bumped.
+	AllowLabels []string `json:"allow_labels"`
+}
+
+// Schedule holds the freeze windows.
+type Schedule struct {
+	windows []window
+}
+
+type window struct {
+	Window
+
+	loc        *time.Location
+	start, end time.Time
+	weekdays   map[time.Weekday]bool
+	from       time.Duration
+	until      time.Duration
+	daily      bool
+}
+
+// NewSchedule validates the windows.
+func NewSchedule(windows []Window) (Schedule, error) {
+	var s Schedule
+	for _, w := range windows {
+		parsed, err := parse(w)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("freeze %q: %s", w.Name, err)
+		}
+		s.windows = append(s.windows, parsed)
+	}
+	return s, nil
+}
+
+// Active returns the first window that is active at t.
+func (s Schedule) Active(t time.Time) (Window, bool) {
+	for _, w := range s.windows {
+		if w.active(t) {
+			return w.Window, true
+		}
+	}
+	return Window{}, false
+}
+
+// Allows reports whether a story with the given labels may be bumped during
+// the window.
+func (w Window) Allows(labels []string) bool {
+	for _, l := range labels {
+		for _, allowed := range w.AllowLabels {
+			if strings.EqualFold(l, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parse(w Window) (window, error) {
+	parsed := window{
+		Window: w,
+		loc:    time.UTC,
+	}
+
+	if w.Start == "" && w.End == "" && len(w.Weekdays) == 0 && w.From == "" && w.Until == "" {
+		return window{}, errors.New("needs a date range, weekdays or times of day")
+	}
+
+	var err error
+	if w.Timezone != "" {
+		parsed.loc, err = time.LoadLocation(w.Timezone)
+		if err != nil {
+			return window{}, err
+		}
+	}
+
+	if w.Start != "" {
+		parsed.start, err = time.ParseInLocation(dateLayout, w.Start, parsed.loc)
+		if err != nil {
+			return window{}, err
+		}
+	}
+	if w.End != "" {
+		parsed.end, err = time.ParseInLocation(dateLayout, w.End, parsed.loc)
+		if err != nil {
+			return window{}, err
+		}
+		parsed.end = parsed.end.AddDate(0, 0, 1)
+	}
+
+	if len(w.Weekdays) > 0 {
+		parsed.weekdays = make(map[time.Weekday]bool)
+		for _, d := range w.Weekdays {
+			wd, ok := weekdays[strings.ToLower(d)]
+			if !ok {
+				return window{}, fmt.Errorf("unknown weekday %q", d)
+			}
+			parsed.weekdays[wd] = true
+		}
+	}
+
+	if (w.From == "") != (w.Until == "") {
+		return window{}, errors.New("needs both from and until")
+	}
+	if w.From != "" {
+		parsed.daily = true
+		parsed.from, err = timeOfDay(w.From)
+		if err != nil {
+			return window{}, err
+		}
+		parsed.until, err = timeOfDay(w.Until)
+		if err != nil {
+			return window{}, err
+		}
+	}
+
+	return parsed, nil
+}
+
+func (w window) active(t time.Time) bool {
+	t = t.In(w.loc)
+
+	if !w.start.IsZero() && t.Before(w.start) {
+		return false
+	}
+	if !w.end.IsZero() && !t.Before(w.end) {
+		return false
+	}
+	if w.weekdays != nil && !w.weekdays[t.Weekday()] {
+		return false
+	}
+
+	if !w.daily {
+		return true
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.loc)
+	tod := t.Sub(midnight)
+	if w.from <= w.until {
+		return tod >= w.from && tod < w.until
+	}
+	return tod >= w.from || tod < w.until
+}
+
+func timeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
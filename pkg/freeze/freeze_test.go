@@ -0,0 +1,99 @@
+package freeze_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/freeze"
+)
+
+var _ = Describe("Schedule", func() {
+	active := func(w freeze.Window, t time.Time) bool {
+		s, err := freeze.NewSchedule([]freeze.Window{w})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, ok := s.Active(t)
+		return ok
+	}
+
+	It("is active during a date range including its last day", func() {
+		w := freeze.Window{Name: "holidays", Start: "2019-12-20", End: "2020-01-02"}
+
+		Expect(active(w, time.Date(2019, 12, 19, 23, 59, 0, 0, time.UTC))).To(BeFalse())
+		Expect(active(w, time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(active(w, time.Date(2020, 1, 2, 23, 59, 0, 0, time.UTC))).To(BeTrue())
+		Expect(active(w, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("is active on weekdays", func() {
+		w := freeze.Window{Name: "weekend", Weekdays: []string{"Saturday", "sunday"}}
+
+		Expect(active(w, time.Date(2019, 3, 8, 12, 0, 0, 0, time.UTC))).To(BeFalse())
+		Expect(active(w, time.Date(2019, 3, 9, 12, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(active(w, time.Date(2019, 3, 10, 12, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("is active between times of day spanning midnight in a timezone", func() {
+		w := freeze.Window{
+			Name:     "nights",
+			From:     "17:00",
+			Until:    "09:00",
+			Timezone: "America/Denver",
+		}
+
+		denver, err := time.LoadLocation("America/Denver")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(active(w, time.Date(2019, 3, 8, 16, 59, 0, 0, denver))).To(BeFalse())
+		Expect(active(w, time.Date(2019, 3, 8, 17, 0, 0, 0, denver))).To(BeTrue())
+		Expect(active(w, time.Date(2019, 3, 9, 8, 59, 0, 0, denver))).To(BeTrue())
+		Expect(active(w, time.Date(2019, 3, 9, 9, 0, 0, 0, denver))).To(BeFalse())
+		Expect(active(w, time.Date(2019, 3, 9, 3, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+
+	It("combines weekdays and times of day", func() {
+		w := freeze.Window{Name: "friday afternoons", Weekdays: []string{"friday"}, From: "12:00", Until: "23:59"}
+
+		Expect(active(w, time.Date(2019, 3, 8, 13, 0, 0, 0, time.UTC))).To(BeTrue())
+		Expect(active(w, time.Date(2019, 3, 8, 11, 0, 0, 0, time.UTC))).To(BeFalse())
+		Expect(active(w, time.Date(2019, 3, 7, 13, 0, 0, 0, time.UTC))).To(BeFalse())
+	})
+
+	It("returns the first active window", func() {
+		s, err := freeze.NewSchedule([]freeze.Window{
+			{Name: "weekend", Weekdays: []string{"saturday"}},
+			{Name: "march", Start: "2019-03-01", End: "2019-03-31", AllowLabels: []string{"hotfix"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		w, ok := s.Active(time.Date(2019, 3, 8, 12, 0, 0, 0, time.UTC))
+		Expect(ok).To(BeTrue())
+		Expect(w.Name).To(Equal("march"))
+	})
+
+	It("rejects invalid windows", func() {
+		for _, w := range []freeze.Window{
+			{Name: "empty"},
+			{Start: "12/20/2019"},
+			{Weekdays: []string{"caturday"}},
+			{From: "5pm", Until: "09:00"},
+			{From: "17:00"},
+			{Weekdays: []string{"monday"}, Timezone: "Mars/Olympus"},
+		} {
+			_, err := freeze.NewSchedule([]freeze.Window{w})
+			Expect(err).To(HaveOccurred(), "%+v", w)
+		}
+	})
+})
+
+var _ = Describe("Window", func() {
+	It("allows stories with an allowed label", func() {
+		w := freeze.Window{AllowLabels: []string{"hotfix"}}
+
+		Expect(w.Allows([]string{"bug", "HotFix"})).To(BeTrue())
+		Expect(w.Allows([]string{"bug"})).To(BeFalse())
+		Expect(freeze.Window{}.Allows([]string{"hotfix"})).To(BeFalse())
+	})
+})
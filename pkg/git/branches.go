@@ -0,0 +1,213 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultReleaseBranches are the branch names tried, in order, for the end
+// of a detected commit range.
+var DefaultReleaseBranches = []string{"release-elect", "release-candidate", "rc", "develop"}
+
+// defaultTargetBranches are tried for the target branch when the default
+// branch of origin is unknown.
+var defaultTargetBranches = []string{"main", "master"}
+
+var rangeRefs = regexp.MustCompile(`^(.*?)\.\.\.?(.*)$`)
+
+// Branches returns the local and remote-tracking branches of the
+// repository.
+func (c GitClient) Branches() ([]string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []string
+	for _, ref := range strings.Fields(buf.String()) {
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		if strings.HasPrefix(ref, "refs/heads/") {
+			branches = append(branches, strings.TrimPrefix(ref, "refs/heads/"))
+			continue
+		}
+		branches = append(branches, strings.TrimPrefix(ref, "refs/remotes/"))
+	}
+	return branches, nil
+}
+
+// DefaultBranch returns the branch the HEAD of remote points to, such as
+// main.
+func (c GitClient) DefaultBranch(remote string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "symbolic-ref", "--quiet", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(buf.String()), "refs/remotes/"+remote+"/"), nil
+}
+
+// DetectRange infers the commit range from the branch conventions of the
+// repository. The range starts at master, which it always started at before
+// it was detected, or else the default branch of origin or main. It ends at
+// the first of the release branches that exists. Local branches are
+// preferred over those of origin unless the client uses remote-tracking
+// refs.
+func (c GitClient) DetectRange(releaseBranches []string) (string, error) {
+	branches, err := c.Branches()
+	if err != nil {
+		return "", err
+	}
+	exists := make(map[string]bool)
+	for _, b := range branches {
+		exists[b] = true
+	}
+	resolve := func(names []string) string {
+		for _, name := range names {
+			if c.remoteTrackingRefs && exists["origin/"+name] {
+				return "origin/" + name
+			}
+			if exists[name] {
+				return name
+			}
+			if exists["origin/"+name] {
+				return "origin/" + name
+			}
+		}
+		return ""
+	}
+
+	targets := []string{"master"}
+	def, err := c.DefaultBranch("origin")
+	if err == nil && def != "" && def != "master" {
+		targets = append(targets, def)
+	}
+	if def != "main" {
+		targets = append(targets, "main")
+	}
+
+	target := resolve(targets)
+	if target == "" {
+		return "", fmt.Errorf(
+			"could not detect the commit range: none of the branches %s exist (available branches: %s)",
+			strings.Join(targets, ", "), listBranches(branches),
+		)
+	}
+	release := resolve(releaseBranches)
+	if release == "" {
+		return "", fmt.Errorf(
+			"could not detect the commit range: none of the release branches %s exist (available branches: %s)",
+			strings.Join(releaseBranches, ", "), listBranches(branches),
+		)
+	}
+
+	return target + ".." + release, nil
+}
+
+// TargetBranch returns the branch at the start of the commit range without
+// the name of its remote. When the range does not start at a branch it is
+// the default branch of origin, or main or master when that is unknown.
+func (c GitClient) TargetBranch(commitRange string) (string, error) {
+	branches, err := c.Branches()
+	if err != nil {
+		return "", err
+	}
+	exists := make(map[string]bool)
+	for _, b := range branches {
+		exists[b] = true
+	}
+
+	m := rangeRefs.FindStringSubmatch(commitRange)
+	if m != nil && exists[m[1]] {
+		remotes, err := c.Remotes()
+		if err != nil {
+			return "", err
+		}
+		for _, r := range remotes {
+			if strings.HasPrefix(m[1], r+"/") {
+				return strings.TrimPrefix(m[1], r+"/"), nil
+			}
+		}
+		return m[1], nil
+	}
+
+	def, err := c.DefaultBranch("origin")
+	if err == nil && def != "" {
+		return def, nil
+	}
+	for _, name := range defaultTargetBranches {
+		if exists[name] || exists["origin/"+name] {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"could not detect the target branch, use -target-branch (available branches: %s)",
+		listBranches(branches),
+	)
+}
+
+// ValidateRange returns an error listing the available branches if either
+// end of the commit range does not exist.
+func (c GitClient) ValidateRange(commitRange string) error {
+	for _, ref := range rangeEnds(commitRange) {
+		err := c.execute(bytes.NewBuffer(nil), "git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+		if err == nil {
+			continue
+		}
+
+		branches, berr := c.Branches()
+		if berr != nil {
+			return fmt.Errorf("%s in commit range %s does not exist", ref, commitRange)
+		}
+		return fmt.Errorf(
+			"%s in commit range %s does not exist (available branches: %s)",
+			ref, commitRange, listBranches(branches),
+		)
+	}
+
+	return nil
+}
+
+// LocalBranches returns the ends of the commit range that are local
+// branches, which fetching does not update.
+func (c GitClient) LocalBranches(commitRange string) []string {
+	var local []string
+	for _, ref := range rangeEnds(commitRange) {
+		err := c.execute(bytes.NewBuffer(nil), "git", "show-ref", "--verify", "--quiet", "refs/heads/"+ref)
+		if err == nil {
+			local = append(local, ref)
+		}
+	}
+	return local
+}
+
+// rangeEnds returns the refs at either end of the commit range that are
+// given.
+func rangeEnds(commitRange string) []string {
+	refs := []string{commitRange}
+	m := rangeRefs.FindStringSubmatch(commitRange)
+	if m != nil {
+		refs = m[1:]
+	}
+
+	var ends []string
+	for _, ref := range refs {
+		if ref != "" {
+			ends = append(ends, ref)
+		}
+	}
+	return ends
+}
+
+func listBranches(branches []string) string {
+	if len(branches) == 0 {
+		return "none"
+	}
+	return strings.Join(branches, ", ")
+}
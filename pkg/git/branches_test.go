@@ -0,0 +1,229 @@
+package git_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("Branches", func() {
+	It("lists local and remote-tracking branches", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\nrefs/heads/release-elect\nrefs/remotes/origin/HEAD\nrefs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		branches, err := gc.Branches()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(branches).To(Equal([]string{"main", "release-elect", "origin/main"}))
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes",
+		}))
+	})
+})
+
+var _ = Describe("DefaultBranch", func() {
+	It("returns the branch the HEAD of the remote points to", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		branch, err := gc.DefaultBranch("origin")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(branch).To(Equal("main"))
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "symbolic-ref", "--quiet", "refs/remotes/origin/HEAD",
+		}))
+	})
+})
+
+var _ = Describe("DetectRange", func() {
+	It("ranges from the default branch of origin to the first release branch", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\nrefs/heads/develop\nrefs/remotes/origin/release-candidate\n"},
+				{output: "refs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		commitRange, err := gc.DetectRange(git.DefaultReleaseBranches)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commitRange).To(Equal("main..origin/release-candidate"))
+	})
+
+	It("starts at master before the default branch of origin", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/master\nrefs/heads/main\nrefs/heads/release-elect\n"},
+				{output: "refs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		commitRange, err := gc.DetectRange(git.DefaultReleaseBranches)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commitRange).To(Equal("master..release-elect"))
+	})
+
+	It("prefers the branches of origin when using remote-tracking refs", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\nrefs/heads/release-elect\nrefs/remotes/origin/main\nrefs/remotes/origin/release-elect\n"},
+				{output: "refs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithRemoteTrackingRefs(),
+		)
+
+		commitRange, err := gc.DetectRange(git.DefaultReleaseBranches)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commitRange).To(Equal("origin/main..origin/release-elect"))
+	})
+
+	It("falls back to master when origin has no HEAD", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/master\nrefs/heads/release-elect\n"},
+				{err: errors.New("not a symbolic ref")},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		commitRange, err := gc.DetectRange(git.DefaultReleaseBranches)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commitRange).To(Equal("master..release-elect"))
+	})
+
+	It("lists the available branches when there is no release branch", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\nrefs/heads/feature\n"},
+				{output: "refs/remotes/origin/main\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		_, err := gc.DetectRange([]string{"release-elect"})
+		Expect(err).To(MatchError(
+			"could not detect the commit range: none of the release branches release-elect exist (available branches: main, feature)",
+		))
+	})
+})
+
+var _ = Describe("TargetBranch", func() {
+	It("is the branch at the start of the range without its remote", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\nrefs/remotes/origin/main\nrefs/remotes/origin/release-elect\n"},
+				{output: "origin\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.TargetBranch("origin/main..origin/release-elect")).To(Equal("main"))
+	})
+
+	It("keeps slashes of local branches", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/release/v2\nrefs/heads/release-elect\n"},
+				{output: "origin\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.TargetBranch("release/v2..release-elect")).To(Equal("release/v2"))
+	})
+
+	It("is the default branch of origin when the range does not start at a branch", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\n"},
+				{output: "refs/remotes/origin/trunk\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.TargetBranch("v1.2.0..abc123")).To(Equal("trunk"))
+	})
+
+	It("falls back to main or master", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "refs/heads/main\n"},
+				{err: errors.New("not a symbolic ref")},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.TargetBranch("")).To(Equal("main"))
+	})
+})
+
+var _ = Describe("ValidateRange", func() {
+	It("verifies both ends of the range", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{{}, {}},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.ValidateRange("main..release-elect")).To(Succeed())
+
+		Expect(se.runCommands).To(HaveLen(2))
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "rev-parse", "--verify", "--quiet", "main^{commit}",
+		}))
+		Expect(se.runCommands[1].Args).To(Equal([]string{
+			"git", "rev-parse", "--verify", "--quiet", "release-elect^{commit}",
+		}))
+	})
+
+	It("lists the available branches when a ref does not exist", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{err: errors.New("exit status 1")},
+				{output: "refs/heads/main\nrefs/heads/release-elect\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		err := gc.ValidateRange("master..release-elect")
+		Expect(err).To(MatchError(
+			"master in commit range master..release-elect does not exist (available branches: main, release-elect)",
+		))
+	})
+})
+
+var _ = Describe("LocalBranches", func() {
+	It("returns the ends of the range that are local branches", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{err: errors.New("exit status 1")},
+				{},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		Expect(gc.LocalBranches("origin/main..release-elect")).To(Equal([]string{"release-elect"}))
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "show-ref", "--verify", "--quiet", "refs/heads/origin/main",
+		}))
+	})
+})
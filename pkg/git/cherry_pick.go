@@ -0,0 +1,71 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ChangedFiles returns the paths changed by the commit relative to its first
+// parent.
+func (c GitClient) ChangedFiles(sha string) ([]string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "diff", "--name-only", sha+"^1", sha)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range strings.Split(buf.String(), "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// CherryPick applies the commits in order on top of base in a temporary
+// worktree and points branch at the result. It returns the sha of the
+// branch.
+func (c GitClient) CherryPick(base, branch string, commits []*Commit) (string, error) {
+	dir, err := ioutil.TempDir("", "bumper-cherry-pick-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	err = c.execute(bytes.NewBuffer(nil), "git", "worktree", "add", "--quiet", "--detach", dir, base)
+	if err != nil {
+		return "", err
+	}
+	defer c.execute(bytes.NewBuffer(nil), "git", "worktree", "remove", "--force", dir)
+
+	for _, commit := range commits {
+		args := []string{"-C", dir, "cherry-pick", "-x"}
+		if len(commit.Parents) > 1 {
+			args = append(args, "-m", "1")
+		}
+
+		err = c.execute(bytes.NewBuffer(nil), "git", append(args, commit.Hash)...)
+		if err != nil {
+			c.execute(bytes.NewBuffer(nil), "git", "-C", dir, "cherry-pick", "--abort")
+			return "", fmt.Errorf("%s does not apply cleanly: %s", commit.ShortSHA(), err)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = c.execute(buf, "git", "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	head := strings.TrimSpace(buf.String())
+
+	err = c.execute(bytes.NewBuffer(nil), "git", "branch", "--force", branch, head)
+	if err != nil {
+		return "", err
+	}
+
+	return head, nil
+}
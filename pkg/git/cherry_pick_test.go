@@ -0,0 +1,111 @@
+package git_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("ChangedFiles", func() {
+	It("lists the files changed by the commit", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "src/a.go\nsrc/b.go\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		files, err := gc.ChangedFiles("abc123")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(files).To(Equal([]string{"src/a.go", "src/b.go"}))
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "diff", "--name-only", "abc123^1", "abc123",
+		}))
+	})
+
+	It("returns an error if git diff fails", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{{err: errors.New("bad revision")}},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		_, err := gc.ChangedFiles("abc123")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CherryPick", func() {
+	var commits []*git.Commit
+
+	BeforeEach(func() {
+		commits = []*git.Commit{
+			{Hash: "aaa111", Parents: []string{"000000"}},
+			{Hash: "bbb222", Parents: []string{"aaa111", "ccc333"}},
+		}
+	})
+
+	It("cherry-picks the commits onto base in a worktree and updates the branch", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{},
+				{},
+				{},
+				{output: "fff999\n"},
+				{},
+				{},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithRepoPath("/tmp/release"),
+		)
+
+		head, err := gc.CherryPick("base123", "bumper/cherry-pick", commits)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(head).To(Equal("fff999"))
+
+		Expect(se.runCommands).To(HaveLen(6))
+		worktree := se.runCommands[0].Args[7]
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "worktree", "add", "--quiet", "--detach", worktree, "base123",
+		}))
+		Expect(se.runCommands[1].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "-C", worktree, "cherry-pick", "-x", "aaa111",
+		}))
+		Expect(se.runCommands[2].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "-C", worktree, "cherry-pick", "-x", "-m", "1", "bbb222",
+		}))
+		Expect(se.runCommands[3].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "-C", worktree, "rev-parse", "HEAD",
+		}))
+		Expect(se.runCommands[4].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "branch", "--force", "bumper/cherry-pick", "fff999",
+		}))
+		Expect(se.runCommands[5].Args).To(Equal([]string{
+			"git", "-C", "/tmp/release", "worktree", "remove", "--force", worktree,
+		}))
+	})
+
+	It("aborts and returns an error when a commit does not apply cleanly", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{},
+				{err: errors.New("conflict")},
+				{},
+				{},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		_, err := gc.CherryPick("base123", "bumper/cherry-pick", commits)
+		Expect(err).To(MatchError(ContainSubstring("aaa111 does not apply cleanly")))
+
+		Expect(se.runCommands).To(HaveLen(4))
+		Expect(se.runCommands[2].Args[3:]).To(Equal([]string{"cherry-pick", "--abort"}))
+		Expect(se.runCommands[3].Args[1:3]).To(Equal([]string{"worktree", "remove"}))
+	})
+})
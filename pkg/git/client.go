@@ -3,7 +3,9 @@ package git
 import (
 	"bufio"
 	"bytes"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,6 +14,14 @@ import (
 var (
 	storyID         = regexp.MustCompile(`\[(?:\w+ )?#(\d+)\]`)
 	submoduleCommit = regexp.MustCompile(`\+Subproject commit ([[:xdigit:]]+)\b`)
+	revertedCommit  = regexp.MustCompile(`This reverts commit ([[:xdigit:]]+)`)
+	mergedBranch    = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'|^Merge pull request #\d+ from (\S+)`)
+
+	// BranchStoryPattern matches story IDs in branch names such as
+	// story/123456-fix-drain or 123456_fix_drain. It also matches dates such
+	// as release-20190305, so it is only used when given to
+	// WithBranchPatterns.
+	BranchStoryPattern = regexp.MustCompile(`(?:^|[/_-])(\d{6,})(?:[/_-]|$)`)
 )
 
 type CommandExecutor interface {
@@ -20,7 +30,16 @@ type CommandExecutor interface {
 
 type GitClient struct {
 	exec           CommandExecutor
+	repoPath       string
 	submodulePaths []string
+	patchIDReverts bool
+	firstParent    bool
+	storyPatterns  []*regexp.Regexp
+	branchPatterns []*regexp.Regexp
+
+	remoteTrackingRefs bool
+	submoduleMirrors   map[string]string
+	env                []string
 }
 
 func NewClient(opts ...ClientOption) GitClient {
@@ -34,8 +53,61 @@ func NewClient(opts ...ClientOption) GitClient {
 }
 
 func (c GitClient) Commits(commitRange string) ([]*Commit, error) {
+	args := []string{"log", "--pretty=format:%H %P"}
+	if c.firstParent {
+		args = append(args, "--first-parent")
+	}
+	args = append(args, commitRange)
+
+	commits, err := c.log(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, commit := range commits {
+		if len(commit.Parents) < 2 {
+			continue
+		}
+
+		if c.firstParent {
+			commit.Merged, err = c.mergedCommits(commit, commitRange)
+			if err != nil {
+				return nil, err
+			}
+			attributeStory(commit.StoryID, commit.Merged)
+			continue
+		}
+
+		if commit.StoryID == 0 {
+			continue
+		}
+
+		hashes, err := c.mergedHashes(commit, commitRange)
+		if err != nil {
+			return nil, err
+		}
+		var merged []*Commit
+		for _, mc := range commits {
+			if hashes[mc.Hash] {
+				merged = append(merged, mc)
+			}
+		}
+		attributeStory(commit.StoryID, merged)
+	}
+
+	if c.patchIDReverts {
+		err = c.matchRevertsByPatchID(commits)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return commits, nil
+}
+
+func (c GitClient) log(args ...string) ([]*Commit, error) {
 	buf := bytes.NewBuffer(nil)
-	err := c.execute(buf, "git", "log", "--pretty=format:%H", commitRange)
+	err := c.execute(buf, "git", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -43,15 +115,21 @@ func (c GitClient) Commits(commitRange string) ([]*Commit, error) {
 	var commits []*Commit
 	br := bufio.NewReader(buf)
 	for {
-		shaBytes, _, err := br.ReadLine()
+		lineBytes, _, err := br.ReadLine()
 		if err != nil {
 			break
 		}
 
-		commit, err := c.buildCommit(string(shaBytes))
+		fields := strings.Fields(string(lineBytes))
+		if len(fields) == 0 {
+			continue
+		}
+
+		commit, err := c.buildCommit(fields[0])
 		if err != nil {
 			return nil, err
 		}
+		commit.Parents = fields[1:]
 
 		commits = append(commits, commit)
 	}
@@ -59,13 +137,175 @@ func (c GitClient) Commits(commitRange string) ([]*Commit, error) {
 	return commits, nil
 }
 
+// mergedCommits returns the commits a merge commit brought in from its
+// side branches that are not already part of the commit range.
+func (c GitClient) mergedCommits(merge *Commit, commitRange string) ([]*Commit, error) {
+	args := append([]string{"log", "--pretty=format:%H %P"}, mergedRevs(merge, commitRange)...)
+
+	return c.log(args...)
+}
+
+func (c GitClient) mergedHashes(merge *Commit, commitRange string) (map[string]bool, error) {
+	buf := bytes.NewBuffer(nil)
+	args := append([]string{"rev-list"}, mergedRevs(merge, commitRange)...)
+	err := c.execute(buf, "git", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]bool)
+	for _, sha := range strings.Fields(buf.String()) {
+		hashes[sha] = true
+	}
+
+	return hashes, nil
+}
+
+func mergedRevs(merge *Commit, commitRange string) []string {
+	revs := []string{"^" + merge.Parents[0]}
+	parts := strings.SplitN(commitRange, "..", 2)
+	if len(parts) == 2 && parts[0] != "" && !strings.HasPrefix(parts[1], ".") {
+		revs = append(revs, "^"+parts[0])
+	}
+
+	return append(revs, merge.Parents[1:]...)
+}
+
+// attributeStory assigns the story of a merge commit to the commits it
+// brought in that don't reference a story themselves.
+func attributeStory(storyID int, merged []*Commit) {
+	if storyID == 0 {
+		return
+	}
+
+	for _, mc := range merged {
+		if mc.StoryID == 0 {
+			mc.StoryID = storyID
+		}
+	}
+}
+
+// Clone clones the repository at uri into dir.
+func (c GitClient) Clone(uri, dir string) error {
+	return c.execute(bytes.NewBuffer(nil), "git", "clone", "--quiet", uri, dir)
+}
+
+// Push updates branch on the remote to sha. The push is rejected unless it
+// is a fast-forward.
+func (c GitClient) Push(remote, sha, branch string) error {
+	return c.execute(
+		bytes.NewBuffer(nil),
+		"git", "push", "--quiet", remote, sha+":refs/heads/"+branch,
+	)
+}
+
+// Fetch fetches the given remotes, or all remotes if none are given.
+func (c GitClient) Fetch(remotes ...string) error {
+	args := []string{"fetch", "--quiet"}
+	if len(remotes) == 0 {
+		args = append(args, "--all")
+	} else {
+		args = append(append(args, "--multiple"), remotes...)
+	}
+
+	return c.execute(bytes.NewBuffer(nil), "git", args...)
+}
+
+// Remotes returns the names of the remotes of the repository.
+func (c GitClient) Remotes() ([]string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "remote")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(buf.String()), nil
+}
+
+// FetchRange fetches the remotes whose remote-tracking branches are used in
+// the commit range, or origin when it uses none, and all remotes of the
+// followed submodules.
+func (c GitClient) FetchRange(commitRange string) error {
+	remotes, err := c.Remotes()
+	if err != nil {
+		return err
+	}
+
+	var fetch []string
+	seen := make(map[string]bool)
+	for _, ref := range rangeEnds(commitRange) {
+		for _, r := range remotes {
+			if strings.HasPrefix(ref, r+"/") && !seen[r] {
+				seen[r] = true
+				fetch = append(fetch, r)
+			}
+		}
+	}
+	if len(fetch) == 0 {
+		fetch = []string{"origin"}
+	}
+
+	err = c.Fetch(fetch...)
+	if err != nil {
+		return err
+	}
+
+	for _, sp := range c.submodulePaths {
+		args := append(c.submoduleGitArgs("HEAD", sp), "fetch", "--quiet", "--all")
+		err = c.execute(bytes.NewBuffer(nil), "git", args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LatestTag returns the most recent tag reachable from ref.
+func (c GitClient) LatestTag(ref string) (string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "describe", "--tags", "--abbrev=0", ref)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// HooksDir returns the directory git runs hooks from.
+func (c GitClient) HooksDir() (string, error) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	dir := strings.TrimSpace(buf.String())
+	if !filepath.IsAbs(dir) && c.repoPath != "" {
+		dir = filepath.Join(c.repoPath, dir)
+	}
+	return dir, nil
+}
+
 func (c GitClient) execute(buf *bytes.Buffer, command string, args ...string) error {
-	cmd := exec.Command(command, args...)
+	cmd := c.command(command, args...)
 	cmd.Stdout = buf
 
 	return c.exec.Run(cmd)
 }
 
+func (c GitClient) command(command string, args ...string) *exec.Cmd {
+	if command == "git" && c.repoPath != "" {
+		args = append([]string{"-C", c.repoPath}, args...)
+	}
+
+	cmd := exec.Command(command, args...)
+	if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+	return cmd
+}
+
 func (c GitClient) buildCommit(sha string) (*Commit, error) {
 	subBuf := bytes.NewBuffer(nil)
 	err := c.execute(subBuf, "git", "show", "--no-patch", "--pretty=format:%s", sha)
@@ -90,14 +330,28 @@ func (c GitClient) buildCommit(sha string) (*Commit, error) {
 		Hash:    sha,
 		Subject: subBuf.String(),
 		StoryID: storyID,
+		Reverts: getRevertedSHA(commitMsgBuf.String()),
 	}
 
 	for _, sp := range c.submodulePaths {
 		if commit.StoryID == 0 {
-			commit.StoryID = c.getBumpedStoryId(idBuf.String(), sp)
+			commit.StoryID = c.getBumpedStoryId(sha, idBuf.String(), sp)
 		}
 	}
 
+	if commit.StoryID == 0 && len(c.storyPatterns) > 0 {
+		msgBuf := bytes.NewBuffer(nil)
+		err = c.execute(msgBuf, "git", "show", "--no-patch", "--format=%B", sha)
+		if err != nil {
+			return nil, err
+		}
+		commit.StoryID = matchStoryID(commitMessage(msgBuf.String()), c.storyPatterns)
+	}
+
+	if commit.StoryID == 0 {
+		commit.StoryID = matchStoryID(getMergedBranch(subBuf.String()), c.branchPatterns)
+	}
+
 	return commit, nil
 }
 
@@ -106,18 +360,134 @@ func (c GitClient) getStoryID(body string) int {
 	if len(result) < 2 {
 		return 0
 	}
-	storyID := result[1]
-	if strings.Contains(body, "bump prometheus") {
-		println("storyID: " + storyID)
-	}
-	id, err := strconv.Atoi(storyID)
+	id, err := strconv.Atoi(result[1])
 	if err != nil {
 		return 0
 	}
 	return id
 }
 
-func (c GitClient) getBumpedStoryId(commitMessage, followBumpOf string) int {
+// matchStoryID returns the story ID captured by the first of the patterns
+// that matches s.
+func matchStoryID(s string, patterns []*regexp.Regexp) int {
+	if s == "" {
+		return 0
+	}
+
+	for _, p := range patterns {
+		result := p.FindStringSubmatch(s)
+		if len(result) < 2 {
+			continue
+		}
+
+		id, err := strconv.Atoi(result[1])
+		if err == nil {
+			return id
+		}
+	}
+	return 0
+}
+
+// commitMessage returns the commit message without the subject of a merge,
+// as it names the merged branch, which is matched by the branch patterns.
+func commitMessage(msg string) string {
+	lines := strings.SplitN(msg, "\n", 2)
+	if getMergedBranch(lines[0]) == "" {
+		return msg
+	}
+	if len(lines) < 2 {
+		return ""
+	}
+	return lines[1]
+}
+
+func getMergedBranch(subject string) string {
+	result := mergedBranch.FindStringSubmatch(subject)
+	if len(result) < 3 {
+		return ""
+	}
+	if result[1] != "" {
+		return result[1]
+	}
+	return result[2]
+}
+
+func getRevertedSHA(body string) string {
+	result := revertedCommit.FindStringSubmatch(body)
+	if len(result) < 2 {
+		return ""
+	}
+	return result[1]
+}
+
+// matchRevertsByPatchID pairs commits that undo an earlier commit in the
+// range without saying so in their message. A commit reverts another when
+// its reversed diff has the same patch-id as the other commit's diff.
+func (c GitClient) matchRevertsByPatchID(commitsDesc []*Commit) error {
+	patchIDs := make(map[string]int)
+	for i, commit := range commitsDesc {
+		id, err := c.patchID(commit.Hash, false)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			continue
+		}
+		if _, ok := patchIDs[id]; !ok {
+			patchIDs[id] = i
+		}
+	}
+
+	for i, commit := range commitsDesc {
+		if commit.Reverts != "" {
+			continue
+		}
+
+		id, err := c.patchID(commit.Hash, true)
+		if err != nil {
+			return err
+		}
+
+		j, ok := patchIDs[id]
+		if id == "" || !ok || j <= i {
+			continue
+		}
+		commit.Reverts = commitsDesc[j].Hash
+	}
+
+	return nil
+}
+
+func (c GitClient) patchID(sha string, reverse bool) (string, error) {
+	args := []string{"show", "--pretty=format:"}
+	if reverse {
+		args = append(args, "-R")
+	}
+	args = append(args, sha)
+
+	diffBuf := bytes.NewBuffer(nil)
+	err := c.execute(diffBuf, "git", args...)
+	if err != nil {
+		return "", err
+	}
+
+	idBuf := bytes.NewBuffer(nil)
+	cmd := c.command("git", "patch-id", "--stable")
+	cmd.Stdin = diffBuf
+	cmd.Stdout = idBuf
+	err = c.exec.Run(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(idBuf.String())
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+func (c GitClient) getBumpedStoryId(sha, commitMessage, followBumpOf string) int {
 	if !strings.Contains(commitMessage, "Bump "+followBumpOf) {
 		return 0
 	}
@@ -129,7 +499,8 @@ func (c GitClient) getBumpedStoryId(commitMessage, followBumpOf string) int {
 
 	submoduleCommitHash := result[1]
 	out := &bytes.Buffer{}
-	c.execute(out, "git", "-C", followBumpOf, "show", "--no-patch", "--pretty=format:%B", submoduleCommitHash)
+	args := append(c.submoduleGitArgs(sha, followBumpOf), "show", "--no-patch", "--pretty=format:%B", submoduleCommitHash)
+	c.execute(out, "git", args...)
 	submoduleCommitMessage := out.String()
 	return c.getStoryID(submoduleCommitMessage)
 }
@@ -142,8 +513,79 @@ func WithCommandExecutor(exec CommandExecutor) ClientOption {
 	}
 }
 
+// WithRepoPath runs git in the repository at the given path instead of the
+// current directory.
+func WithRepoPath(path string) ClientOption {
+	return func(c *GitClient) {
+		c.repoPath = path
+	}
+}
+
 func WithFollowBumpsOf(submodulePaths ...string) ClientOption {
 	return func(c *GitClient) {
 		c.submodulePaths = submodulePaths
 	}
 }
+
+// WithFirstParent restricts the commits to the first-parent chain of the
+// range. Commits brought in by merges are attached to the merge commit.
+func WithFirstParent() ClientOption {
+	return func(c *GitClient) {
+		c.firstParent = true
+	}
+}
+
+// WithStoryPatterns adds patterns that are matched against the commit
+// message when it has no story tag, e.g. to find stories in pull request
+// titles. The first capture group of each pattern must be the story ID.
+func WithStoryPatterns(patterns ...*regexp.Regexp) ClientOption {
+	return func(c *GitClient) {
+		c.storyPatterns = patterns
+	}
+}
+
+// WithBranchPatterns sets the patterns used to find story IDs in the branch
+// names of merge commit subjects. The first capture group of each pattern
+// must be the story ID. Branch names are not matched by default.
+func WithBranchPatterns(patterns ...*regexp.Regexp) ClientOption {
+	return func(c *GitClient) {
+		c.branchPatterns = patterns
+	}
+}
+
+// WithEnv adds environment variables, given as KEY=value, to the git
+// commands of the client only.
+func WithEnv(env ...string) ClientOption {
+	return func(c *GitClient) {
+		c.env = env
+	}
+}
+
+// WithRemoteTrackingRefs makes detected commit ranges use the branches of
+// origin rather than local branches, e.g. after fetching.
+func WithRemoteTrackingRefs() ClientOption {
+	return func(c *GitClient) {
+		c.remoteTrackingRefs = true
+	}
+}
+
+// WithSubmoduleMirrors reads the followed submodules from bare mirrors,
+// keyed by the submodule URL in .gitmodules, so no checkout is needed.
+// Submodules without a mirror are read from the modules directory of the
+// repository's git dir.
+func WithSubmoduleMirrors(mirrors map[string]string) ClientOption {
+	return func(c *GitClient) {
+		c.submoduleMirrors = mirrors
+		if c.submoduleMirrors == nil {
+			c.submoduleMirrors = make(map[string]string)
+		}
+	}
+}
+
+// WithPatchIDReverts enables detecting reverts by comparing patch-ids when
+// the commit message does not reference the reverted commit.
+func WithPatchIDReverts() ClientOption {
+	return func(c *GitClient) {
+		c.patchIDReverts = true
+	}
+}
@@ -3,6 +3,7 @@ package git_test
 import (
 	"errors"
 	"os/exec"
+	"regexp"
 
 	"github.com/loggregator/bumper/pkg/git"
 	. "github.com/onsi/ginkgo"
@@ -13,17 +14,22 @@ var _ = Describe("Client", func() {
 	It("gets commits hashes for a given range", func() {
 		se := &stubCommandExecutor{
 			runResults: []runResult{
-				{output: "f00dface\ndeadbeef\n123456\n789abc\ndef123\n"},
+				{output: "f00dface deadbeef\ndeadbeef 123456\n123456 789abc\n789abc def123\ndef123 000000\n"},
 				{output: "Fifth Commit [Delivers #55555555]\n"},
 				{output: "Fifth Commit [Delivers #55555555]\n"},
+				{output: "Fifth Commit [Delivers #55555555]\n"},
+				{output: "Fourth Commit [fixes #44444444]\n"},
 				{output: "Fourth Commit [fixes #44444444]\n"},
 				{output: "Fourth Commit [fixes #44444444]\n"},
 				{output: "Third Commit\n"},
 				{output: "Third Commit\n\n[#33333333]\n"},
+				{output: "Third Commit\n\n[#33333333]\n"},
 				{output: "Second Commit\n"},
 				{output: "Second Commit\n\n[#22222222]\n"},
+				{output: "Second Commit\n\n[#22222222]\n"},
 				{output: "First Commit\n"},
 				{output: "First Commit\n\n[finishes #11111111]\n"},
+				{output: "First Commit\n\n[finishes #11111111]\n"},
 			},
 		}
 		gc := git.NewClient(git.WithCommandExecutor(se))
@@ -31,61 +37,289 @@ var _ = Describe("Client", func() {
 		commits, err := gc.Commits("master..release-elect")
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(se.runCommands).To(HaveLen(11))
+		Expect(se.runCommands).To(HaveLen(16))
 		Expect(se.runCommands[0].Args).To(Equal([]string{
-			"git", "log", "--pretty=format:%H", "master..release-elect",
+			"git", "log", "--pretty=format:%H %P", "master..release-elect",
 		}))
 
-		Expect(se.runCommands[1].Args).To(Equal([]string{
-			"git", "show", "--no-patch", "--pretty=format:%s", "f00dface",
-		}))
-		Expect(se.runCommands[2].Args).To(Equal([]string{
-			"git", "show", "--pretty=format:%B", "f00dface",
+		for i, sha := range []string{"f00dface", "deadbeef", "123456", "789abc", "def123"} {
+			Expect(se.runCommands[3*i+1].Args).To(Equal([]string{
+				"git", "show", "--no-patch", "--pretty=format:%s", sha,
+			}))
+			Expect(se.runCommands[3*i+2].Args).To(Equal([]string{
+				"git", "show", "--pretty=format:%B", sha,
+			}))
+			Expect(se.runCommands[3*i+3].Args).To(Equal([]string{
+				"git", "log", "-n", "1", sha,
+			}))
+		}
+
+		Expect(commits).To(HaveLen(5))
+		Expect(commits).To(Equal([]*git.Commit{
+			{Hash: "f00dface", Subject: "Fifth Commit [Delivers #55555555]\n", StoryID: 55555555, Parents: []string{"deadbeef"}},
+			{Hash: "deadbeef", Subject: "Fourth Commit [fixes #44444444]\n", StoryID: 44444444, Parents: []string{"123456"}},
+			{Hash: "123456", Subject: "Third Commit\n", StoryID: 33333333, Parents: []string{"789abc"}},
+			{Hash: "789abc", Subject: "Second Commit\n", StoryID: 22222222, Parents: []string{"def123"}},
+			{Hash: "def123", Subject: "First Commit\n", StoryID: 11111111, Parents: []string{"000000"}},
 		}))
-		Expect(se.runCommands[3].Args).To(Equal([]string{
-			"git", "show", "--no-patch", "--pretty=format:%s", "deadbeef",
+	})
+
+	It("follows the first parent and attaches merged commits", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc def123\n789abc 000000\n"},
+				{output: "Merge branch 'story/44444444-feature'\n"},
+				{output: "Merge branch 'story/44444444-feature'\n"},
+				{output: "Merge branch 'story/44444444-feature'\n"},
+				{output: "First Commit\n"},
+				{output: "First Commit\n"},
+				{output: "First Commit [#11111111]\n"},
+				{output: "def123 fed321\nfed321 000000\n"},
+				{output: "Side Commit\n"},
+				{output: "Side Commit\n"},
+				{output: "Side Commit [#22222222]\n"},
+				{output: "Other Side Commit\n"},
+				{output: "Other Side Commit\n"},
+				{output: "Other Side Commit\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithBranchPatterns(git.BranchStoryPattern),
+			git.WithCommandExecutor(se),
+			git.WithFirstParent(),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "log", "--pretty=format:%H %P", "--first-parent", "master..release-elect",
 		}))
-		Expect(se.runCommands[4].Args).To(Equal([]string{
-			"git", "show", "--pretty=format:%B", "deadbeef",
+		Expect(se.runCommands[7].Args).To(Equal([]string{
+			"git", "log", "--pretty=format:%H %P", "^789abc", "^master", "def123",
 		}))
-		Expect(se.runCommands[5].Args).To(Equal([]string{
-			"git", "show", "--no-patch", "--pretty=format:%s", "123456",
+
+		Expect(commits).To(HaveLen(2))
+		Expect(commits[0].Parents).To(Equal([]string{"789abc", "def123"}))
+		Expect(commits[0].Merged).To(HaveLen(2))
+		Expect(commits[0].Merged[0].StoryID).To(Equal(22222222))
+		Expect(commits[0].Merged[1].StoryID).To(Equal(44444444))
+		Expect(commits[1].Merged).To(BeEmpty())
+	})
+
+	It("gets the story ID from the branch name of a merge commit", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc def123\n"},
+				{output: "Merge branch 'story/12345678-fix-drain'\n"},
+				{output: "Merge branch 'story/12345678-fix-drain'\n"},
+				{output: "Merge branch 'story/12345678-fix-drain'\n"},
+				{output: "def123\nfed321\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithBranchPatterns(git.BranchStoryPattern),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits).To(HaveLen(1))
+		Expect(commits[0].StoryID).To(Equal(12345678))
+	})
+
+	It("attributes the story of a merge commit to the commits it brought in", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc def123\ndef123 789abc\n789abc 000000\n"},
+				{output: "Merge pull request #42 from org/12345678-fix-drain\n"},
+				{output: "Merge pull request #42 from org/12345678-fix-drain\n"},
+				{output: "Merge pull request #42 from org/12345678-fix-drain\n\nFix drain\n"},
+				{output: "Side Commit\n"},
+				{output: "Side Commit\n"},
+				{output: "Side Commit\n"},
+				{output: "First Commit\n"},
+				{output: "First Commit\n"},
+				{output: "First Commit [#11111111]\n"},
+				{output: "def123\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithBranchPatterns(git.BranchStoryPattern),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[10].Args).To(Equal([]string{
+			"git", "rev-list", "^789abc", "^master", "def123",
 		}))
-		Expect(se.runCommands[6].Args).To(Equal([]string{
-			"git", "show", "--pretty=format:%B", "123456",
+
+		Expect(commits).To(HaveLen(3))
+		Expect(commits[0].StoryID).To(Equal(12345678))
+		Expect(commits[1].StoryID).To(Equal(12345678))
+		Expect(commits[2].StoryID).To(Equal(11111111))
+	})
+
+	It("gets the story ID using configured patterns", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Fix drain (#42)\n"},
+				{output: "Fix drain (#42)\n\nStory: 12345678\n"},
+				{output: "Fix drain (#42)\n\nStory: 12345678\n"},
+				{output: "Fix drain (#42)\n\nStory: 12345678\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithStoryPatterns(regexp.MustCompile(`Story: (\d+)`)),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits).To(HaveLen(1))
+		Expect(commits[0].StoryID).To(Equal(12345678))
+		Expect(se.runCommands[4].Args).To(Equal([]string{
+			"git", "show", "--no-patch", "--format=%B", "123456",
 		}))
+	})
+
+	It("matches story patterns against the commit message only", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Fix drain\n"},
+				{output: "Fix drain\n\ndiff --cc drain.go\n++\tlimit := 12345678\n"},
+				{output: "commit 1234567890abcdef\nDate: 20190305\n\n    Fix drain\n"},
+				{output: "Fix drain\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithStoryPatterns(regexp.MustCompile(`(\d{6,})`)),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits[0].StoryID).To(BeZero())
+	})
+
+	It("matches story patterns against the title of a pull request merge", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Merge pull request #42 from org/fix-drain\n"},
+				{output: "Merge pull request #42 from org/fix-drain\n\nFix drain (#12345678)\n"},
+				{output: "Merge pull request #42 from org/fix-drain\n\nFix drain (#12345678)\n"},
+				{output: "Merge pull request #42 from org/fix-drain\n\nFix drain (#12345678)\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithStoryPatterns(regexp.MustCompile(`#(\d+)`)),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits[0].StoryID).To(Equal(12345678))
+	})
+
+	It("does not match branch names by default", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc def123\n"},
+				{output: "Merge branch 'release-20190305'\n"},
+				{output: "Merge branch 'release-20190305'\n"},
+				{output: "Merge branch 'release-20190305'\n"},
+				{output: "def123\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits[0].StoryID).To(BeZero())
+	})
+
+	It("records the commit a revert reverts", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Revert \"Add feature\"\n"},
+				{output: "Revert \"Add feature\"\n\nThis reverts commit 789abc.\n"},
+				{output: "Revert \"Add feature\"\n\nThis reverts commit 789abc.\n"},
+			},
+		}
+		gc := git.NewClient(git.WithCommandExecutor(se))
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(commits).To(HaveLen(1))
+		Expect(commits[0].Reverts).To(Equal("789abc"))
+	})
+
+	It("detects reverts by patch-id when enabled", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n789abc 000000\n"},
+				{output: "Undo feature\n"},
+				{output: "Undo feature\n"},
+				{output: "Undo feature\n"},
+				{output: "Add feature\n"},
+				{output: "Add feature\n"},
+				{output: "Add feature\n"},
+				{output: "diff-undo"},
+				{output: "aaaaaa 123456\n"},
+				{output: "diff-add"},
+				{output: "bbbbbb 789abc\n"},
+				{output: "diff-undo-reversed"},
+				{output: "bbbbbb 123456\n"},
+				{output: "diff-add-reversed"},
+				{output: "aaaaaa 789abc\n"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithPatchIDReverts(),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands).To(HaveLen(15))
 		Expect(se.runCommands[7].Args).To(Equal([]string{
-			"git", "show", "--no-patch", "--pretty=format:%s", "789abc",
+			"git", "show", "--pretty=format:", "123456",
 		}))
 		Expect(se.runCommands[8].Args).To(Equal([]string{
-			"git", "show", "--pretty=format:%B", "789abc",
-		}))
-		Expect(se.runCommands[9].Args).To(Equal([]string{
-			"git", "show", "--no-patch", "--pretty=format:%s", "def123",
+			"git", "patch-id", "--stable",
 		}))
-		Expect(se.runCommands[10].Args).To(Equal([]string{
-			"git", "show", "--pretty=format:%B", "def123",
+		Expect(se.runCommands[11].Args).To(Equal([]string{
+			"git", "show", "--pretty=format:", "-R", "123456",
 		}))
 
-		Expect(commits).To(HaveLen(5))
-		Expect(commits).To(Equal([]*git.Commit{
-			{Hash: "f00dface", Subject: "Fifth Commit [Delivers #55555555]\n", StoryID: 55555555},
-			{Hash: "deadbeef", Subject: "Fourth Commit [fixes #44444444]\n", StoryID: 44444444},
-			{Hash: "123456", Subject: "Third Commit\n", StoryID: 33333333},
-			{Hash: "789abc", Subject: "Second Commit\n", StoryID: 22222222},
-			{Hash: "def123", Subject: "First Commit\n", StoryID: 11111111},
-		}))
+		Expect(commits[0].Reverts).To(Equal("789abc"))
+		Expect(commits[1].Reverts).To(BeEmpty())
 	})
 
 	It("gets commits of submodules", func() {
 		se := &stubCommandExecutor{
 			runResults: []runResult{
-				{output: "123456\n789012\n"},
+				{output: "123456 789012\n789012 345678\n"},
 				{output: "Third Commit\n"},
 				{output: "Bump src/bumper1\n\n  Username:\n    Update Bumper\n\n\n+Subproject commit ab321c"},
+				{output: "Bump src/bumper1\n\n  Username:\n    Update Bumper\n"},
 				{output: "Sub Commit\n\n[#44444444]"},
 				{output: "Second Commit\n"},
 				{output: "Bump src/bumper2\n\n  Username:\n    Update Bumper\n\n\n+Subproject commit cd432b"},
+				{output: "Bump src/bumper2\n\n  Username:\n    Update Bumper\n"},
 				{output: "Sub Commit\n\n[#55555555]"},
 			},
 		}
@@ -97,9 +331,9 @@ var _ = Describe("Client", func() {
 		commits, err := gc.Commits("master..release-elect")
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(se.runCommands).To(HaveLen(7))
+		Expect(se.runCommands).To(HaveLen(9))
 		Expect(se.runCommands[0].Args).To(Equal([]string{
-			"git", "log", "--pretty=format:%H", "master..release-elect",
+			"git", "log", "--pretty=format:%H %P", "master..release-elect",
 		}))
 
 		Expect(se.runCommands[1].Args).To(Equal([]string{
@@ -109,17 +343,232 @@ var _ = Describe("Client", func() {
 			"git", "show", "--pretty=format:%B", "123456",
 		}))
 		Expect(se.runCommands[3].Args).To(Equal([]string{
+			"git", "log", "-n", "1", "123456",
+		}))
+		Expect(se.runCommands[4].Args).To(Equal([]string{
 			"git", "-C", "src/bumper1", "show", "--no-patch", "--pretty=format:%B", "ab321c",
 		}))
-		Expect(se.runCommands[6].Args).To(Equal([]string{
+		Expect(se.runCommands[8].Args).To(Equal([]string{
 			"git", "-C", "src/bumper2", "show", "--no-patch", "--pretty=format:%B", "cd432b",
 		}))
 
 		Expect(commits).To(HaveLen(2))
 		Expect(commits).To(Equal([]*git.Commit{
-			{Hash: "123456", Subject: "Third Commit\n", StoryID: 44444444},
-			{Hash: "789012", Subject: "Second Commit\n", StoryID: 55555555},
+			{Hash: "123456", Subject: "Third Commit\n", StoryID: 44444444, Parents: []string{"789012"}},
+			{Hash: "789012", Subject: "Second Commit\n", StoryID: 55555555, Parents: []string{"345678"}},
+		}))
+	})
+
+	It("runs git in the configured repository", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Bump src/bumper1\n"},
+				{output: "Bump src/bumper1\n\n+Subproject commit ab321c"},
+				{output: "Bump src/bumper1\n"},
+				{output: "Sub Commit\n\n[#44444444]"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithRepoPath("/repos/release"),
+			git.WithFollowBumpsOf("src/bumper1"),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[0].Args).To(Equal([]string{
+			"git", "-C", "/repos/release", "log", "--pretty=format:%H %P", "master..release-elect",
+		}))
+		Expect(se.runCommands[4].Args).To(Equal([]string{
+			"git", "-C", "/repos/release", "-C", "src/bumper1", "show", "--no-patch", "--pretty=format:%B", "ab321c",
 		}))
+		Expect(commits[0].StoryID).To(Equal(44444444))
+	})
+
+	Describe("Clone", func() {
+		It("clones the repository", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.Clone("https://example.com/release.git", "/tmp/release")).To(Succeed())
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "clone", "--quiet", "https://example.com/release.git", "/tmp/release",
+			}))
+		})
+	})
+
+	Describe("Push", func() {
+		It("pushes the sha to the branch", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{}},
+			}
+			gc := git.NewClient(
+				git.WithCommandExecutor(se),
+				git.WithRepoPath("/tmp/release"),
+			)
+
+			Expect(gc.Push("origin", "abc123", "master")).To(Succeed())
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "-C", "/tmp/release", "push", "--quiet", "origin", "abc123:refs/heads/master",
+			}))
+		})
+
+		It("returns an error if git push fails", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{err: errors.New("non-fast-forward")}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.Push("origin", "abc123", "master")).ToNot(Succeed())
+		})
+
+		It("runs git with the given environment", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{}},
+			}
+			gc := git.NewClient(
+				git.WithCommandExecutor(se),
+				git.WithEnv("GIT_SSH_COMMAND=ssh -i /tmp/key"),
+			)
+
+			Expect(gc.Push("origin", "abc123", "master")).To(Succeed())
+			Expect(se.runCommands[0].Env).To(ContainElement("GIT_SSH_COMMAND=ssh -i /tmp/key"))
+		})
+	})
+
+	Describe("LatestTag", func() {
+		It("returns the most recent tag reachable from the ref", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{output: "v102.3\n"}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.LatestTag("master")).To(Equal("v102.3"))
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "describe", "--tags", "--abbrev=0", "master",
+			}))
+		})
+
+		It("returns an error if there are no tags", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{err: errors.New("No names found")}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			_, err := gc.LatestTag("master")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("HooksDir", func() {
+		It("returns the hooks directory relative to the repo", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{output: ".git/hooks\n"}},
+			}
+			gc := git.NewClient(
+				git.WithCommandExecutor(se),
+				git.WithRepoPath("/tmp/release"),
+			)
+
+			Expect(gc.HooksDir()).To(Equal("/tmp/release/.git/hooks"))
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "-C", "/tmp/release", "rev-parse", "--git-path", "hooks",
+			}))
+		})
+
+		It("returns absolute hooks directories as they are", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{output: "/etc/git-hooks\n"}},
+			}
+			gc := git.NewClient(
+				git.WithCommandExecutor(se),
+				git.WithRepoPath("/tmp/release"),
+			)
+
+			Expect(gc.HooksDir()).To(Equal("/etc/git-hooks"))
+		})
+	})
+
+	Describe("Fetch", func() {
+		It("fetches all remotes", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.Fetch()).To(Succeed())
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "fetch", "--quiet", "--all",
+			}))
+		})
+
+		It("fetches the given remotes", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.Fetch("origin", "upstream")).To(Succeed())
+			Expect(se.runCommands[0].Args).To(Equal([]string{
+				"git", "fetch", "--quiet", "--multiple", "origin", "upstream",
+			}))
+		})
+
+		It("returns an error if git fetch fails", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{{err: errors.New("could not fetch")}},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.Fetch()).ToNot(Succeed())
+		})
+	})
+
+	Describe("FetchRange", func() {
+		It("fetches the remotes of the range and the followed submodules", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{
+					{output: "origin\nupstream\n"},
+					{},
+					{},
+				},
+			}
+			gc := git.NewClient(
+				git.WithCommandExecutor(se),
+				git.WithRepoPath("/tmp/release"),
+				git.WithFollowBumpsOf("src/loggregator"),
+			)
+
+			Expect(gc.FetchRange("upstream/main..origin/release-elect")).To(Succeed())
+
+			Expect(se.runCommands).To(HaveLen(3))
+			Expect(se.runCommands[1].Args).To(Equal([]string{
+				"git", "-C", "/tmp/release", "fetch", "--quiet", "--multiple", "upstream", "origin",
+			}))
+			Expect(se.runCommands[2].Args).To(Equal([]string{
+				"git", "-C", "/tmp/release", "-C", "src/loggregator", "fetch", "--quiet", "--all",
+			}))
+		})
+
+		It("fetches origin when the range only uses local branches", func() {
+			se := &stubCommandExecutor{
+				runResults: []runResult{
+					{output: "origin\n"},
+					{},
+				},
+			}
+			gc := git.NewClient(git.WithCommandExecutor(se))
+
+			Expect(gc.FetchRange("story/123..release-elect")).To(Succeed())
+
+			Expect(se.runCommands[1].Args).To(Equal([]string{
+				"git", "fetch", "--quiet", "--multiple", "origin",
+			}))
+		})
 	})
 
 	It("returns an error if git log fails", func() {
@@ -3,11 +3,31 @@ package git
 import "strings"
 
 type Commit struct {
-	Hash      string
-	Subject   string
-	StoryID   int
-	StoryName string
-	Accepted  bool
+	Hash      string `json:"sha"`
+	Subject   string `json:"subject"`
+	StoryID   int    `json:"story_id"`
+	StoryName string `json:"story_name"`
+	Accepted  bool   `json:"accepted"`
+
+	// Parents are the SHAs of the commit's parents. A nil slice means the
+	// parents are unknown.
+	Parents []string `json:"parents,omitempty"`
+	// Merged are the commits a merge commit brought in from its side
+	// branches when only the first-parent chain is followed.
+	Merged []*Commit `json:"merged,omitempty"`
+
+	// Reverts is the SHA of the commit this commit reverts, if any.
+	Reverts string `json:"reverts,omitempty"`
+	// Reverted is set when the commit and its revert are both in the
+	// range and cancel each other out.
+	Reverted bool `json:"reverted,omitempty"`
+	// CIFailed is set when the commit could have been bumped to but its
+	// build is not green.
+	CIFailed bool `json:"ci_failed,omitempty"`
+	// Soaking is set when the story was accepted too recently to be bumped.
+	Soaking bool `json:"soaking,omitempty"`
+	// Frozen is set when the commit is held back by a release freeze.
+	Frozen bool `json:"frozen,omitempty"`
 }
 
 func (c *Commit) ShortSHA() string {
@@ -25,3 +45,8 @@ func (c *Commit) FormatSubject(length int) string {
 
 	return c.Subject[0:length-3] + "..."
 }
+
+// WithMerged returns the commit followed by the commits it merged.
+func (c *Commit) WithMerged() []*Commit {
+	return append([]*Commit{c}, c.Merged...)
+}
@@ -0,0 +1,27 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ExecCommandExecutor runs commands and includes their stderr in errors.
+type ExecCommandExecutor struct{}
+
+func (ExecCommandExecutor) Run(cmd *exec.Cmd) error {
+	stderrBuf := &strings.Builder{}
+	cmd.Stderr = stderrBuf
+	err := cmd.Run()
+
+	if err != nil {
+		return fmt.Errorf(
+			`failed to execute "%s": %s (stderr: "%s")`,
+			strings.Join(cmd.Args, " "),
+			err,
+			strings.TrimRight(stderrBuf.String(), "\r\n"),
+		)
+	}
+
+	return nil
+}
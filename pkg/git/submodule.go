@@ -0,0 +1,62 @@
+package git
+
+import (
+	"bytes"
+	"strings"
+)
+
+// submoduleGitArgs returns the git arguments that read the followed
+// submodule at path as of the superproject commit sha. Without mirrors the
+// submodule must be checked out at path.
+func (c GitClient) submoduleGitArgs(sha, path string) []string {
+	if c.submoduleMirrors == nil {
+		return []string{"-C", path}
+	}
+
+	name, url := c.submodule(sha, path)
+	if mirror, ok := c.submoduleMirrors[url]; ok {
+		return []string{"--git-dir", mirror}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "rev-parse", "--git-path", "modules/"+name)
+	if err != nil {
+		return []string{"-C", path}
+	}
+	return []string{"--git-dir", strings.TrimSpace(buf.String())}
+}
+
+// submodule returns the name and URL of the submodule at path from the
+// .gitmodules of the superproject commit sha. The name is the path when
+// .gitmodules does not list it.
+func (c GitClient) submodule(sha, path string) (string, string) {
+	buf := bytes.NewBuffer(nil)
+	err := c.execute(buf, "git", "config", "--blob", sha+":.gitmodules", "--get-regexp", `^submodule\..*\.(path|url)$`)
+	if err != nil {
+		return path, ""
+	}
+
+	paths := make(map[string]string)
+	urls := make(map[string]string)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		key := strings.TrimPrefix(fields[0], "submodule.")
+		i := strings.LastIndex(key, ".")
+		switch key[i+1:] {
+		case "path":
+			paths[fields[1]] = key[:i]
+		case "url":
+			urls[key[:i]] = fields[1]
+		}
+	}
+
+	name, ok := paths[path]
+	if !ok {
+		return path, ""
+	}
+	return name, urls[name]
+}
@@ -0,0 +1,107 @@
+package git_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+var _ = Describe("Submodule mirrors", func() {
+	const gitmodules = "submodule.loggregator.path src/loggregator\n" +
+		"submodule.loggregator.url https://github.com/cloudfoundry/loggregator\n" +
+		"submodule.agent.path src/agent\n" +
+		"submodule.agent.url https://github.com/cloudfoundry/agent\n"
+
+	It("reads bumped submodule commits from the mirror of their URL", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Bump src/loggregator\n"},
+				{output: "Bump src/loggregator\n\n+Subproject commit ab321c"},
+				{output: "Bump src/loggregator\n"},
+				{output: gitmodules},
+				{output: "Sub Commit\n\n[#44444444]"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithRepoPath("/mirrors/release.git"),
+			git.WithFollowBumpsOf("src/loggregator"),
+			git.WithSubmoduleMirrors(map[string]string{
+				"https://github.com/cloudfoundry/loggregator": "/mirrors/loggregator.git",
+			}),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[4].Args).To(Equal([]string{
+			"git", "-C", "/mirrors/release.git", "config", "--blob", "123456:.gitmodules",
+			"--get-regexp", `^submodule\..*\.(path|url)$`,
+		}))
+		Expect(se.runCommands[5].Args).To(Equal([]string{
+			"git", "-C", "/mirrors/release.git", "--git-dir", "/mirrors/loggregator.git",
+			"show", "--no-patch", "--pretty=format:%B", "ab321c",
+		}))
+		Expect(commits[0].StoryID).To(Equal(44444444))
+	})
+
+	It("falls back to the modules directory of the git dir", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Bump src/agent\n"},
+				{output: "Bump src/agent\n\n+Subproject commit ab321c"},
+				{output: "Bump src/agent\n"},
+				{output: gitmodules},
+				{output: ".git/modules/agent\n"},
+				{output: "Sub Commit\n\n[#55555555]"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithFollowBumpsOf("src/agent"),
+			git.WithSubmoduleMirrors(nil),
+		)
+
+		commits, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[5].Args).To(Equal([]string{
+			"git", "rev-parse", "--git-path", "modules/agent",
+		}))
+		Expect(se.runCommands[6].Args).To(Equal([]string{
+			"git", "--git-dir", ".git/modules/agent", "show", "--no-patch", "--pretty=format:%B", "ab321c",
+		}))
+		Expect(commits[0].StoryID).To(Equal(55555555))
+	})
+
+	It("uses the path as the name when .gitmodules can not be read", func() {
+		se := &stubCommandExecutor{
+			runResults: []runResult{
+				{output: "123456 789abc\n"},
+				{output: "Bump src/agent\n"},
+				{output: "Bump src/agent\n\n+Subproject commit ab321c"},
+				{output: "Bump src/agent\n"},
+				{err: errors.New("no .gitmodules")},
+				{output: "modules/src/agent\n"},
+				{output: "Sub Commit\n\n[#55555555]"},
+			},
+		}
+		gc := git.NewClient(
+			git.WithCommandExecutor(se),
+			git.WithFollowBumpsOf("src/agent"),
+			git.WithSubmoduleMirrors(nil),
+		)
+
+		_, err := gc.Commits("master..release-elect")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(se.runCommands[5].Args).To(Equal([]string{
+			"git", "rev-parse", "--git-path", "modules/src/agent",
+		}))
+	})
+})
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the public GitHub API.
+const DefaultBaseURL = "https://api.github.com"
+
+// RequestClient sends requests to the GitHub API.
+type RequestClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// PullRequest is a pull request from Head into Base.
+type PullRequest struct {
+	Number int    `json:"number,omitempty"`
+	URL    string `json:"html_url,omitempty"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+}
+
+// Client talks to the GitHub API for a single repository.
+type Client struct {
+	repo       string
+	baseURL    string
+	token      string
+	httpClient RequestClient
+}
+
+type ClientOption func(*Client)
+
+// NewClient creates a client for the repository given as owner/name.
+func NewClient(repo string, opts ...ClientOption) *Client {
+	c := &Client{
+		repo:       repo,
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// WithBaseURL sets the base URL of the API, e.g. for GitHub Enterprise.
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+func WithToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithHTTPClient(httpClient RequestClient) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// UpdateBranch points the branch at the sha, creating it if it does not
+// exist.
+func (c *Client) UpdateBranch(branch, sha string) error {
+	status, err := c.do("GET", c.repoPath("git/ref/heads/"+branch), nil, nil)
+	if err != nil && status != http.StatusNotFound {
+		return err
+	}
+
+	if status == http.StatusNotFound {
+		_, err = c.do("POST", c.repoPath("git/refs"), map[string]string{
+			"ref": "refs/heads/" + branch,
+			"sha": sha,
+		}, nil)
+		return err
+	}
+
+	_, err = c.do("PATCH", c.repoPath("git/refs/heads/"+branch), map[string]interface{}{
+		"sha":   sha,
+		"force": true,
+	}, nil)
+	return err
+}
+
+// OpenPullRequest opens the pull request, or updates the title and body of
+// the open pull request from the same head into the same base.
+func (c *Client) OpenPullRequest(pr PullRequest) (PullRequest, error) {
+	owner := strings.SplitN(c.repo, "/", 2)[0]
+	q := url.Values{
+		"state": {"open"},
+		"head":  {owner + ":" + pr.Head},
+		"base":  {pr.Base},
+	}
+
+	var open []struct {
+		Number int `json:"number"`
+	}
+	_, err := c.do("GET", c.repoPath("pulls?"+q.Encode()), nil, &open)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	var result PullRequest
+	if len(open) > 0 {
+		_, err = c.do("PATCH", c.repoPath(fmt.Sprintf("pulls/%d", open[0].Number)), map[string]string{
+			"title": pr.Title,
+			"body":  pr.Body,
+		}, &result)
+		return result, err
+	}
+
+	_, err = c.do("POST", c.repoPath("pulls"), pr, &result)
+	return result, err
+}
+
+func (c *Client) repoPath(p string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", c.baseURL, c.repo, p)
+}
+
+func (c *Client) do(method, u string, in, out interface{}) (int, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("%s %s: %s: %s", method, u, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return resp.StatusCode, nil
+	}
+
+	return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,161 @@
+package github_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/github"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		api *fakeAPI
+		ts  *httptest.Server
+		c   *github.Client
+	)
+
+	BeforeEach(func() {
+		api = &fakeAPI{responses: make(map[string]response)}
+		ts = httptest.NewServer(api)
+		c = github.NewClient("loggregator/bumper",
+			github.WithBaseURL(ts.URL+"/"),
+			github.WithToken("some-token"),
+		)
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	Describe("UpdateBranch", func() {
+		It("moves an existing branch to the sha", func() {
+			api.responses["GET /repos/loggregator/bumper/git/ref/heads/bump"] = response{status: 200, body: `{}`}
+			api.responses["PATCH /repos/loggregator/bumper/git/refs/heads/bump"] = response{status: 200, body: `{}`}
+
+			Expect(c.UpdateBranch("bump", "abc123")).To(Succeed())
+
+			Expect(api.requests).To(HaveLen(2))
+			Expect(api.requests[1].body).To(MatchJSON(`{"sha": "abc123", "force": true}`))
+			Expect(api.requests[1].auth).To(Equal("token some-token"))
+		})
+
+		It("creates the branch when it does not exist", func() {
+			api.responses["GET /repos/loggregator/bumper/git/ref/heads/bump"] = response{status: 404, body: `{}`}
+			api.responses["POST /repos/loggregator/bumper/git/refs"] = response{status: 201, body: `{}`}
+
+			Expect(c.UpdateBranch("bump", "abc123")).To(Succeed())
+
+			Expect(api.requests).To(HaveLen(2))
+			Expect(api.requests[1].body).To(MatchJSON(`{"ref": "refs/heads/bump", "sha": "abc123"}`))
+		})
+
+		It("returns an error when the API fails", func() {
+			api.responses["GET /repos/loggregator/bumper/git/ref/heads/bump"] = response{status: 500, body: `oops`}
+
+			Expect(c.UpdateBranch("bump", "abc123")).To(MatchError(ContainSubstring("oops")))
+		})
+	})
+
+	Describe("OpenPullRequest", func() {
+		var pr github.PullRequest
+
+		BeforeEach(func() {
+			pr = github.PullRequest{
+				Title: "Bump to abc123",
+				Body:  "report",
+				Head:  "bump",
+				Base:  "master",
+			}
+		})
+
+		It("opens a pull request", func() {
+			api.responses["GET /repos/loggregator/bumper/pulls"] = response{status: 200, body: `[]`}
+			api.responses["POST /repos/loggregator/bumper/pulls"] = response{
+				status: 201,
+				body:   `{"number": 7, "html_url": "https://github.com/loggregator/bumper/pull/7"}`,
+			}
+
+			result, err := c.OpenPullRequest(pr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.Number).To(Equal(7))
+			Expect(result.URL).To(Equal("https://github.com/loggregator/bumper/pull/7"))
+			Expect(api.requests[0].query).To(Equal("base=master&head=loggregator%3Abump&state=open"))
+			Expect(api.requests[1].body).To(MatchJSON(`{
+				"title": "Bump to abc123",
+				"body": "report",
+				"head": "bump",
+				"base": "master"
+			}`))
+		})
+
+		It("updates the open pull request", func() {
+			api.responses["GET /repos/loggregator/bumper/pulls"] = response{status: 200, body: `[{"number": 7}]`}
+			api.responses["PATCH /repos/loggregator/bumper/pulls/7"] = response{
+				status: 200,
+				body:   `{"number": 7, "html_url": "https://github.com/loggregator/bumper/pull/7"}`,
+			}
+
+			result, err := c.OpenPullRequest(pr)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.Number).To(Equal(7))
+			Expect(api.requests).To(HaveLen(2))
+			Expect(api.requests[1].body).To(MatchJSON(`{"title": "Bump to abc123", "body": "report"}`))
+		})
+
+		It("returns an error when the API fails", func() {
+			api.responses["GET /repos/loggregator/bumper/pulls"] = response{status: 200, body: `[]`}
+			api.responses["POST /repos/loggregator/bumper/pulls"] = response{status: 422, body: `invalid`}
+
+			_, err := c.OpenPullRequest(pr)
+			Expect(err).To(MatchError(ContainSubstring("422")))
+		})
+	})
+})
+
+type response struct {
+	status int
+	body   string
+}
+
+type request struct {
+	query string
+	auth  string
+	body  string
+}
+
+type fakeAPI struct {
+	mu        sync.Mutex
+	responses map[string]response
+	requests  []request
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	f.requests = append(f.requests, request{
+		query: r.URL.RawQuery,
+		auth:  r.Header.Get("Authorization"),
+		body:  string(body),
+	})
+
+	resp, ok := f.responses[r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery]
+	if !ok {
+		resp, ok = f.responses[r.Method+" "+r.URL.Path]
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(resp.status)
+	w.Write([]byte(resp.body))
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Green reports whether the combined commit status and the check runs of the
+// sha all succeeded. A commit without any statuses or check runs is not
+// green.
+func (c *Client) Green(sha string) (bool, error) {
+	var status struct {
+		State      string `json:"state"`
+		TotalCount int    `json:"total_count"`
+	}
+	_, err := c.do("GET", c.repoPath("commits/"+sha+"/status"), nil, &status)
+	if err != nil {
+		return false, err
+	}
+	if status.TotalCount > 0 && status.State != "success" {
+		return false, nil
+	}
+
+	runs, total, err := c.checkRuns(sha)
+	if err != nil {
+		return false, err
+	}
+	if len(runs) < total {
+		return false, nil
+	}
+
+	for _, run := range runs {
+		if run.Status != "completed" {
+			return false, nil
+		}
+
+		switch run.Conclusion {
+		case "success", "neutral", "skipped":
+		default:
+			return false, nil
+		}
+	}
+
+	return status.TotalCount > 0 || total > 0, nil
+}
+
+type checkRun struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+// checkRuns returns the check runs of the sha from every page and the total
+// count reported by GitHub.
+func (c *Client) checkRuns(sha string) ([]checkRun, int, error) {
+	var runs []checkRun
+	for page := 1; ; page++ {
+		var checks struct {
+			TotalCount int        `json:"total_count"`
+			CheckRuns  []checkRun `json:"check_runs"`
+		}
+		q := url.Values{
+			"per_page": {"100"},
+			"page":     {strconv.Itoa(page)},
+		}
+		code, err := c.do("GET", c.repoPath("commits/"+sha+"/check-runs?"+q.Encode()), nil, &checks)
+		if code == http.StatusNotFound {
+			return runs, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		runs = append(runs, checks.CheckRuns...)
+		if len(checks.CheckRuns) == 0 || len(runs) >= checks.TotalCount {
+			return runs, checks.TotalCount, nil
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package github_test
+
+import (
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/github"
+)
+
+var _ = Describe("Green", func() {
+	var (
+		api *fakeAPI
+		ts  *httptest.Server
+		c   *github.Client
+	)
+
+	BeforeEach(func() {
+		api = &fakeAPI{responses: make(map[string]response)}
+		ts = httptest.NewServer(api)
+		c = github.NewClient("loggregator/bumper", github.WithBaseURL(ts.URL))
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	setStatus := func(body string) {
+		api.responses["GET /repos/loggregator/bumper/commits/abc123/status"] = response{status: 200, body: body}
+	}
+	setChecks := func(body string) {
+		api.responses["GET /repos/loggregator/bumper/commits/abc123/check-runs"] = response{status: 200, body: body}
+	}
+
+	It("is green when statuses and check runs succeeded", func() {
+		setStatus(`{"state": "success", "total_count": 2}`)
+		setChecks(`{"total_count": 2, "check_runs": [
+			{"status": "completed", "conclusion": "success"},
+			{"status": "completed", "conclusion": "skipped"}
+		]}`)
+
+		Expect(c.Green("abc123")).To(BeTrue())
+	})
+
+	It("is green with only check runs", func() {
+		setStatus(`{"state": "pending", "total_count": 0}`)
+		setChecks(`{"total_count": 1, "check_runs": [{"status": "completed", "conclusion": "success"}]}`)
+
+		Expect(c.Green("abc123")).To(BeTrue())
+	})
+
+	It("is not green when a status failed", func() {
+		setStatus(`{"state": "failure", "total_count": 1}`)
+
+		Expect(c.Green("abc123")).To(BeFalse())
+	})
+
+	It("is not green when a check run is still running", func() {
+		setStatus(`{"state": "success", "total_count": 1}`)
+		setChecks(`{"total_count": 1, "check_runs": [{"status": "in_progress"}]}`)
+
+		Expect(c.Green("abc123")).To(BeFalse())
+	})
+
+	It("is not green when a check run failed", func() {
+		setStatus(`{"state": "pending", "total_count": 0}`)
+		setChecks(`{"total_count": 1, "check_runs": [{"status": "completed", "conclusion": "failure"}]}`)
+
+		Expect(c.Green("abc123")).To(BeFalse())
+	})
+
+	It("reads every page of check runs", func() {
+		setStatus(`{"state": "success", "total_count": 1}`)
+		checksPath := "GET /repos/loggregator/bumper/commits/abc123/check-runs"
+		api.responses[checksPath+"?page=1&per_page=100"] = response{status: 200, body: `{"total_count": 2, "check_runs": [
+			{"status": "completed", "conclusion": "success"}
+		]}`}
+		api.responses[checksPath+"?page=2&per_page=100"] = response{status: 200, body: `{"total_count": 2, "check_runs": [
+			{"status": "completed", "conclusion": "failure"}
+		]}`}
+
+		Expect(c.Green("abc123")).To(BeFalse())
+		Expect(api.requests).To(HaveLen(3))
+	})
+
+	It("is not green when fewer check runs are listed than counted", func() {
+		setStatus(`{"state": "success", "total_count": 1}`)
+		checksPath := "GET /repos/loggregator/bumper/commits/abc123/check-runs"
+		api.responses[checksPath+"?page=1&per_page=100"] = response{status: 200, body: `{"total_count": 31, "check_runs": [
+			{"status": "completed", "conclusion": "success"}
+		]}`}
+		api.responses[checksPath+"?page=2&per_page=100"] = response{status: 200, body: `{"total_count": 31, "check_runs": []}`}
+
+		Expect(c.Green("abc123")).To(BeFalse())
+	})
+
+	It("is not green without any statuses or check runs", func() {
+		setStatus(`{"state": "pending", "total_count": 0}`)
+		setChecks(`{"total_count": 0, "check_runs": []}`)
+
+		Expect(c.Green("abc123")).To(BeFalse())
+	})
+
+	It("returns an error when the API fails", func() {
+		api.responses["GET /repos/loggregator/bumper/commits/abc123/status"] = response{status: 500, body: `oops`}
+
+		_, err := c.Green("abc123")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,33 @@
+package hook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const marker = "# Installed by bumper."
+
+// Install writes a pre-push hook running command into hooksDir. An existing
+// hook is only replaced if bumper installed it.
+func Install(hooksDir, command string) error {
+	path := filepath.Join(hooksDir, "pre-push")
+
+	existing, err := ioutil.ReadFile(path)
+	if err == nil && !strings.Contains(string(existing), marker) {
+		return fmt.Errorf("%s already exists and was not installed by bumper", path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = os.MkdirAll(hooksDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s \"$@\"\n", marker, command)
+	return ioutil.WriteFile(path, []byte(script), 0755)
+}
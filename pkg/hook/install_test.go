@@ -0,0 +1,58 @@
+package hook_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/hook"
+)
+
+var _ = Describe("Install", func() {
+	var hooksDir string
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "bumper-hooks-")
+		Expect(err).ToNot(HaveOccurred())
+		hooksDir = filepath.Join(dir, "hooks")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(hooksDir))
+	})
+
+	It("writes an executable pre-push hook", func() {
+		Expect(hook.Install(hooksDir, "/usr/local/bin/bumper pre-push")).To(Succeed())
+
+		path := filepath.Join(hooksDir, "pre-push")
+		script, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(script)).To(Equal(
+			"#!/bin/sh\n# Installed by bumper.\nexec /usr/local/bin/bumper pre-push \"$@\"\n",
+		))
+
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+	})
+
+	It("replaces a hook it installed", func() {
+		Expect(hook.Install(hooksDir, "bumper pre-push")).To(Succeed())
+		Expect(hook.Install(hooksDir, "bumper pre-push -target-branch main")).To(Succeed())
+
+		script, err := ioutil.ReadFile(filepath.Join(hooksDir, "pre-push"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(script)).To(ContainSubstring("-target-branch main"))
+	})
+
+	It("does not replace other hooks", func() {
+		Expect(os.MkdirAll(hooksDir, 0755)).To(Succeed())
+		path := filepath.Join(hooksDir, "pre-push")
+		Expect(ioutil.WriteFile(path, []byte("#!/bin/sh\nmake test\n"), 0755)).To(Succeed())
+
+		Expect(hook.Install(hooksDir, "bumper pre-push")).To(MatchError(ContainSubstring("not installed by bumper")))
+	})
+})
@@ -0,0 +1,98 @@
+package hook
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+)
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+type Bumper interface {
+	Bump() (bumper.Result, error)
+}
+
+// Update is a ref the pre-push hook is told is being pushed.
+type Update struct {
+	LocalRef  string
+	LocalSHA  string
+	RemoteRef string
+	RemoteSHA string
+}
+
+// ParseUpdates reads the updates git passes to the pre-push hook on stdin.
+func ParseUpdates(r io.Reader) ([]Update, error) {
+	var updates []Update
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid pre-push line %q", s.Text())
+		}
+
+		updates = append(updates, Update{
+			LocalRef:  fields[0],
+			LocalSHA:  fields[1],
+			RemoteRef: fields[2],
+			RemoteSHA: fields[3],
+		})
+	}
+	return updates, s.Err()
+}
+
+// PrePush refuses pushes to a branch beyond the bump SHA of the pushed
+// commits.
+type PrePush struct {
+	branch    string
+	newBumper func(commitRange string) Bumper
+}
+
+// NewPrePush checks pushes to branch, bumping the pushed range with the
+// bumper returned by newBumper.
+func NewPrePush(branch string, newBumper func(commitRange string) Bumper) PrePush {
+	return PrePush{
+		branch:    branch,
+		newBumper: newBumper,
+	}
+}
+
+// Check returns an error if an update pushes commits to the branch beyond
+// the bump SHA. Deleting or creating the branch is not checked.
+func (p PrePush) Check(updates []Update) error {
+	for _, u := range updates {
+		if u.RemoteRef != "refs/heads/"+p.branch {
+			continue
+		}
+		if u.LocalSHA == zeroSHA || u.RemoteSHA == zeroSHA {
+			continue
+		}
+
+		r, err := p.newBumper(u.RemoteSHA + ".." + u.LocalSHA).Bump()
+		if err != nil {
+			return err
+		}
+		if r.BumpSHA == u.LocalSHA || len(r.Commits) == 0 {
+			continue
+		}
+
+		beyond := r.Remaining()
+		if r.BumpSHA == "" {
+			return fmt.Errorf(
+				"refusing to push to %s: none of the %d pushed commits can be bumped",
+				p.branch, len(beyond),
+			)
+		}
+		return fmt.Errorf(
+			"refusing to push to %s: %d commits are beyond the bump SHA %s",
+			p.branch, len(beyond), r.BumpSHA,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package hook_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/hook"
+)
+
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+var _ = Describe("ParseUpdates", func() {
+	It("parses the refs being pushed", func() {
+		updates, err := hook.ParseUpdates(strings.NewReader(
+			"refs/heads/master 222222 refs/heads/master 111111\n" +
+				"refs/heads/feature 333333 refs/heads/feature " + zeroSHA + "\n",
+		))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(updates).To(Equal([]hook.Update{
+			{LocalRef: "refs/heads/master", LocalSHA: "222222", RemoteRef: "refs/heads/master", RemoteSHA: "111111"},
+			{LocalRef: "refs/heads/feature", LocalSHA: "333333", RemoteRef: "refs/heads/feature", RemoteSHA: zeroSHA},
+		}))
+	})
+
+	It("returns an error for invalid lines", func() {
+		_, err := hook.ParseUpdates(strings.NewReader("refs/heads/master 222222\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PrePush", func() {
+	var (
+		sb     *stubBumper
+		ranges []string
+		p      hook.PrePush
+	)
+
+	BeforeEach(func() {
+		sb = &stubBumper{}
+		ranges = nil
+		p = hook.NewPrePush("master", func(commitRange string) hook.Bumper {
+			ranges = append(ranges, commitRange)
+			return sb
+		})
+	})
+
+	update := func(local, remote string) []hook.Update {
+		return []hook.Update{
+			{LocalRef: "refs/heads/master", LocalSHA: local, RemoteRef: "refs/heads/master", RemoteSHA: remote},
+		}
+	}
+
+	It("allows pushing up to the bump SHA", func() {
+		sb.result = bumper.Result{
+			Commits: []*git.Commit{{Hash: "333333"}, {Hash: "222222"}},
+			BumpSHA: "333333",
+		}
+
+		Expect(p.Check(update("333333", "111111"))).To(Succeed())
+		Expect(ranges).To(Equal([]string{"111111..333333"}))
+	})
+
+	It("refuses pushing beyond the bump SHA", func() {
+		sb.result = bumper.Result{
+			Commits: []*git.Commit{{Hash: "333333"}, {Hash: "222222"}},
+			BumpSHA: "222222",
+		}
+
+		err := p.Check(update("333333", "111111"))
+		Expect(err).To(MatchError("refusing to push to master: 1 commits are beyond the bump SHA 222222"))
+	})
+
+	It("refuses pushing when nothing can be bumped", func() {
+		sb.result = bumper.Result{
+			Commits: []*git.Commit{{Hash: "333333"}, {Hash: "222222"}},
+		}
+
+		err := p.Check(update("333333", "111111"))
+		Expect(err).To(MatchError("refusing to push to master: none of the 2 pushed commits can be bumped"))
+	})
+
+	It("ignores other branches, creations and deletions", func() {
+		updates := []hook.Update{
+			{LocalRef: "refs/heads/feature", LocalSHA: "333333", RemoteRef: "refs/heads/feature", RemoteSHA: "111111"},
+			{LocalRef: "refs/heads/master", LocalSHA: "333333", RemoteRef: "refs/heads/master", RemoteSHA: zeroSHA},
+			{LocalRef: "(delete)", LocalSHA: zeroSHA, RemoteRef: "refs/heads/master", RemoteSHA: "111111"},
+		}
+
+		Expect(p.Check(updates)).To(Succeed())
+		Expect(ranges).To(BeEmpty())
+	})
+
+	It("returns an error if the bump fails", func() {
+		sb.err = errors.New("bad revision")
+
+		Expect(p.Check(update("333333", "111111"))).To(MatchError("bad revision"))
+	})
+})
+
+type stubBumper struct {
+	result bumper.Result
+	err    error
+}
+
+func (s *stubBumper) Bump() (bumper.Result, error) {
+	return s.result, s.err
+}
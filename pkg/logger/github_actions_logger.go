@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/report"
+)
+
+// IsGitHubActions reports whether bumper is running in GitHub Actions.
+func IsGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// GitHubActionsLogger writes the bump to the step outputs and summary of a
+// GitHub Actions job and annotates blocking commits with warnings.
+type GitHubActionsLogger struct {
+	writer      io.Writer
+	outputPath  string
+	summaryPath string
+
+	commitRange string
+	commits     []*git.Commit
+	freeze      *freeze.Window
+}
+
+func NewGitHubActionsLogger(opts ...GitHubActionsLoggerOption) *GitHubActionsLogger {
+	l := &GitHubActionsLogger{
+		writer:      os.Stdout,
+		outputPath:  os.Getenv("GITHUB_OUTPUT"),
+		summaryPath: os.Getenv("GITHUB_STEP_SUMMARY"),
+	}
+
+	for _, o := range opts {
+		o(l)
+	}
+
+	return l
+}
+
+func (l *GitHubActionsLogger) Header(commitRange string) {
+	l.commitRange = commitRange
+}
+
+// Freeze shows the active release freeze in the summary.
+func (l *GitHubActionsLogger) Freeze(w freeze.Window) {
+	l.freeze = &w
+}
+
+func (l *GitHubActionsLogger) Commit(c *git.Commit) {
+	l.commits = append(l.commits, c)
+}
+
+func (l *GitHubActionsLogger) Footer(bumpSHA string) {
+	r := bumper.Result{
+		CommitRange: l.commitRange,
+		Commits:     l.commits,
+		BumpSHA:     bumpSHA,
+		Freeze:      l.freeze,
+	}
+	blockers := r.Blockers()
+
+	for _, c := range r.Remaining() {
+		if c.Reverted || (c.Accepted && !c.CIFailed) {
+			continue
+		}
+
+		title, message := annotation(c)
+		fmt.Fprintf(
+			l.writer,
+			"::warning title=%s::%s\n",
+			escapeProperty(title),
+			escapeData(message),
+		)
+	}
+	fmt.Fprintln(l.writer, bumpSHA)
+
+	var stories []string
+	seen := make(map[int]bool)
+	for _, c := range blockers {
+		if c.StoryID == 0 || seen[c.StoryID] {
+			continue
+		}
+		seen[c.StoryID] = true
+		stories = append(stories, fmt.Sprint(c.StoryID))
+	}
+
+	l.appendTo(l.outputPath, fmt.Sprintf(
+		"bump_sha=%s\nblocked=%t\nblocking_stories=%s\n",
+		bumpSHA,
+		len(r.Remaining()) > 0,
+		strings.Join(stories, ","),
+	))
+	l.appendTo(l.summaryPath, report.Markdown(r))
+}
+
+// annotation returns the title and message of the warning for a commit
+// holding back the bump, worded by why it does.
+func annotation(c *git.Commit) (string, string) {
+	commit := c.ShortSHA() + " " + strings.TrimSpace(c.Subject)
+	story := ""
+	if c.StoryID != 0 {
+		story = fmt.Sprintf(" (%s)", c.StoryName)
+	}
+
+	switch {
+	case c.CIFailed:
+		return "Build is not green", commit + " can't be bumped to as its build is not green"
+	case c.Soaking:
+		return fmt.Sprintf("Story %d is soaking", c.StoryID),
+			commit + " was accepted too recently" + story
+	case c.Frozen && c.StoryID == 0:
+		return "Held back by the release freeze", commit + " is held back by the release freeze"
+	case c.Frozen:
+		return fmt.Sprintf("Story %d is held back by the release freeze", c.StoryID),
+			commit + " is held back by the release freeze" + story
+	case c.StoryID == 0:
+		return "Blocked", commit + " is not accepted"
+	default:
+		return fmt.Sprintf("Blocked by story %d", c.StoryID),
+			commit + " is not accepted" + story
+	}
+}
+
+func (l *GitHubActionsLogger) appendTo(path, content string) {
+	if path == "" {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("failed to open %s: %s", path, err)
+		return
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	if err != nil {
+		log.Printf("failed to write %s: %s", path, err)
+	}
+}
+
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+type GitHubActionsLoggerOption func(*GitHubActionsLogger)
+
+func WithGitHubActionsWriter(w io.Writer) GitHubActionsLoggerOption {
+	return func(l *GitHubActionsLogger) {
+		l.writer = w
+	}
+}
+
+// WithOutputPath sets the file step outputs are appended to. Defaults to
+// $GITHUB_OUTPUT.
+func WithOutputPath(path string) GitHubActionsLoggerOption {
+	return func(l *GitHubActionsLogger) {
+		l.outputPath = path
+	}
+}
+
+// WithSummaryPath sets the file the step summary is appended to. Defaults
+// to $GITHUB_STEP_SUMMARY.
+func WithSummaryPath(path string) GitHubActionsLoggerOption {
+	return func(l *GitHubActionsLogger) {
+		l.summaryPath = path
+	}
+}
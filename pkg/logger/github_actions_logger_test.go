@@ -0,0 +1,149 @@
+package logger_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+var _ = Describe("GitHubActionsLogger", func() {
+	var (
+		buf         *bytes.Buffer
+		dir         string
+		outputPath  string
+		summaryPath string
+		gl          *logger.GitHubActionsLogger
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "bumper-github-actions")
+		Expect(err).ToNot(HaveOccurred())
+
+		buf = bytes.NewBuffer(nil)
+		outputPath = filepath.Join(dir, "output")
+		summaryPath = filepath.Join(dir, "summary")
+		gl = logger.NewGitHubActionsLogger(
+			logger.WithGitHubActionsWriter(buf),
+			logger.WithOutputPath(outputPath),
+			logger.WithSummaryPath(summaryPath),
+		)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	readFile := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(b)
+	}
+
+	It("writes outputs, summary and annotations for a blocked bump", func() {
+		gl.Header("master..release-elect")
+		gl.Commit(&git.Commit{
+			Hash:      "DEF456ABC123",
+			Subject:   "Add drain",
+			StoryID:   22222222,
+			StoryName: "Drains, with care",
+		})
+		gl.Commit(&git.Commit{
+			Hash:      "ABC123DEF456",
+			Subject:   "Fix | pipe",
+			StoryID:   11111111,
+			StoryName: "Pipes",
+			Accepted:  true,
+		})
+		gl.Footer("ABC123DEF456")
+
+		Expect(buf.String()).To(Equal(
+			"::warning title=Blocked by story 22222222::DEF456AB Add drain is not accepted (Drains, with care)\n" +
+				"ABC123DEF456\n",
+		))
+		Expect(readFile(outputPath)).To(Equal(
+			"bump_sha=ABC123DEF456\nblocked=true\nblocking_stories=22222222\n",
+		))
+		Expect(readFile(summaryPath)).To(Equal(
+			"## Bump of `master..release-elect`\n\n" +
+				"This is the commit you should bump to: `ABC123DEF456`\n\n" +
+				"| | Commit | Subject | Story |\n" +
+				"|---|---|---|---|\n" +
+				"| ✗ | `DEF456AB` | Add drain | [#22222222](https://www.pivotaltracker.com/story/show/22222222) Drains, with care |\n" +
+				"| ✓ | `ABC123DE` | Fix \\| pipe | [#11111111](https://www.pivotaltracker.com/story/show/11111111) Pipes |\n\n",
+		))
+	})
+
+	It("words the annotations by why the commits hold back the bump", func() {
+		gl.Header("master..release-elect")
+		gl.Commit(&git.Commit{Hash: "AAAA1111BBBB", Subject: "Soak", StoryID: 1, StoryName: "One", Soaking: true})
+		gl.Commit(&git.Commit{Hash: "BBBB2222CCCC", Subject: "Freeze", StoryID: 2, StoryName: "Two", Frozen: true})
+		gl.Commit(&git.Commit{Hash: "CCCC3333DDDD", Subject: "Chore", Frozen: true})
+		gl.Commit(&git.Commit{Hash: "DDDD4444EEEE", Subject: "Build", StoryID: 4, StoryName: "Four", Accepted: true, CIFailed: true})
+		gl.Commit(&git.Commit{Hash: "EEEE5555FFFF", Subject: "Drain", StoryID: 5, StoryName: "Five"})
+		gl.Footer("")
+
+		Expect(buf.String()).To(Equal(
+			"::warning title=Story 1 is soaking::AAAA1111 Soak was accepted too recently (One)\n" +
+				"::warning title=Story 2 is held back by the release freeze::BBBB2222 Freeze is held back by the release freeze (Two)\n" +
+				"::warning title=Held back by the release freeze::CCCC3333 Chore is held back by the release freeze\n" +
+				"::warning title=Build is not green::DDDD4444 Build can't be bumped to as its build is not green\n" +
+				"::warning title=Blocked by story 5::EEEE5555 Drain is not accepted (Five)\n" +
+				"\n",
+		))
+		Expect(readFile(outputPath)).To(ContainSubstring("blocking_stories=1,2,5\n"))
+	})
+
+	It("writes outputs for an unblocked bump", func() {
+		gl.Header("master..release-elect")
+		gl.Commit(&git.Commit{
+			Hash:     "ABC123DEF456",
+			Subject:  "Fix pipe",
+			Accepted: true,
+		})
+		gl.Footer("ABC123DEF456")
+
+		Expect(readFile(outputPath)).To(Equal(
+			"bump_sha=ABC123DEF456\nblocked=false\nblocking_stories=\n",
+		))
+	})
+
+	It("writes outputs when there are no commits", func() {
+		gl.Header("master..release-elect")
+		gl.Footer("")
+
+		Expect(readFile(outputPath)).To(Equal(
+			"bump_sha=\nblocked=false\nblocking_stories=\n",
+		))
+		Expect(readFile(summaryPath)).To(Equal(
+			"## Bump of `master..release-elect`\n\nThere are no commits to bump!\n\n",
+		))
+	})
+
+	It("shows the active release freeze in the summary", func() {
+		ml := logger.NewMultiLogger(gl)
+
+		ml.Header("master..release-elect")
+		ml.Freeze(freeze.Window{Name: "holidays"})
+		ml.Footer("")
+
+		Expect(readFile(summaryPath)).To(ContainSubstring("> **Release freeze holidays is active.**"))
+	})
+
+	It("appends to existing files", func() {
+		Expect(ioutil.WriteFile(outputPath, []byte("other=value\n"), 0644)).To(Succeed())
+
+		gl.Header("master..release-elect")
+		gl.Footer("")
+
+		Expect(readFile(outputPath)).To(HavePrefix("other=value\nbump_sha="))
+	})
+})
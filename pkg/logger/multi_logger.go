@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+// MultiLogger passes everything it logs to each of its loggers.
+type MultiLogger struct {
+	loggers []bumper.Logger
+}
+
+func NewMultiLogger(loggers ...bumper.Logger) *MultiLogger {
+	return &MultiLogger{
+		loggers: loggers,
+	}
+}
+
+func (l *MultiLogger) Header(commitRange string) {
+	for _, ll := range l.loggers {
+		ll.Header(commitRange)
+	}
+}
+
+// Freeze passes the active release freeze to the loggers that take it.
+func (l *MultiLogger) Freeze(w freeze.Window) {
+	for _, ll := range l.loggers {
+		if fl, ok := ll.(bumper.FreezeLogger); ok {
+			fl.Freeze(w)
+		}
+	}
+}
+
+func (l *MultiLogger) Commit(c *git.Commit) {
+	for _, ll := range l.loggers {
+		ll.Commit(c)
+	}
+}
+
+func (l *MultiLogger) Footer(bumpSHA string) {
+	for _, ll := range l.loggers {
+		ll.Footer(bumpSHA)
+	}
+}
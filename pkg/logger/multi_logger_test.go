@@ -0,0 +1,34 @@
+package logger_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/logger"
+)
+
+var _ = Describe("MultiLogger", func() {
+	It("logs to every logger", func() {
+		buf1 := bytes.NewBuffer(nil)
+		buf2 := bytes.NewBuffer(nil)
+		ml := logger.NewMultiLogger(
+			logger.NewLogger(logger.WithWriter(buf1)),
+			logger.NewVerboseLogger(
+				logger.WithVerboseWriter(buf2),
+				logger.WithColorDisabled(),
+			),
+		)
+
+		ml.Header("master..release-elect")
+		ml.Commit(&git.Commit{Hash: "abc123", Subject: "Commit", Accepted: true})
+		ml.Footer("abc123")
+
+		Expect(buf1.String()).To(Equal("abc123\n"))
+		Expect(buf2.String()).To(ContainSubstring("master..release-elect"))
+		Expect(buf2.String()).To(ContainSubstring("✓ abc123 Commit"))
+		Expect(buf2.String()).To(ContainSubstring("This is the commit you should bump to: abc123"))
+	})
+})
@@ -35,19 +35,26 @@ func (l *VerboseLogger) Header(commitRange string) {
 }
 
 func (l *VerboseLogger) Commit(c *git.Commit) {
-	storyID := fmt.Sprint(c.StoryID)
-	if storyID == "0" {
-		storyID = "~~~~~~~~~"
-	}
-
 	fmt.Fprintln(
 		l.writer,
 		l.formatAccepted(c),
 		l.yellow(c.ShortSHA()),
 		c.FormatSubject(40),
-		l.blue(storyID),
+		l.blue(formatStoryID(c.StoryID)),
 		c.StoryName,
 	)
+
+	for _, mc := range c.Merged {
+		fmt.Fprintln(
+			l.writer,
+			" ",
+			l.formatAccepted(mc),
+			l.yellow(mc.ShortSHA()),
+			mc.FormatSubject(36),
+			l.blue(formatStoryID(mc.StoryID)),
+			mc.StoryName,
+		)
+	}
 }
 
 func (l *VerboseLogger) Footer(bumpSHA string) {
@@ -75,7 +82,31 @@ func WithColorDisabled() VerboseLoggerOption {
 	}
 }
 
+func formatStoryID(storyID int) string {
+	if storyID == 0 {
+		return "~~~~~~~~~"
+	}
+
+	return fmt.Sprint(storyID)
+}
+
 func (l *VerboseLogger) formatAccepted(c *git.Commit) string {
+	if c.Reverted {
+		return l.yellow("↺")
+	}
+
+	if c.CIFailed {
+		return l.red("⚠")
+	}
+
+	if c.Soaking {
+		return l.yellow("⧗")
+	}
+
+	if c.Frozen {
+		return l.blue("❄")
+	}
+
 	if c.Accepted || c.StoryID == 0 {
 		return l.green("✓")
 	}
@@ -76,6 +76,86 @@ var _ = Describe("VerboseLogger", func() {
 				"",
 			}))
 		})
+
+		It("logs the commits brought in by a merge commit", func() {
+			vl.Commit(&git.Commit{
+				Hash:     "ABC123DEF456",
+				Subject:  "Merge branch 'feature'",
+				Accepted: true,
+				Merged: []*git.Commit{
+					{
+						Hash:      "DEF456ABC123",
+						Subject:   "Add feature",
+						StoryID:   12345678,
+						StoryName: "My awesome story name",
+						Accepted:  true,
+					},
+				},
+			})
+			Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+				"\033[32m✓\033[0m \033[33mABC123DE\033[0m Merge branch 'feature'                   \033[34m~~~~~~~~~\033[0m ",
+				"  \033[32m✓\033[0m \033[33mDEF456AB\033[0m Add feature                          \033[34m12345678\033[0m My awesome story name",
+				"",
+			}))
+		})
+
+		It("logs the commit with ↺ when the commit is reverted in the range", func() {
+			vl.Commit(&git.Commit{
+				Hash:      "ABC123DEF456",
+				Subject:   "Update bumper to be awesome",
+				StoryID:   12345678,
+				StoryName: "My awesome story name",
+				Accepted:  false,
+				Reverted:  true,
+			})
+			Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+				"\033[33m↺\033[0m \033[33mABC123DE\033[0m Update bumper to be awesome              \033[34m12345678\033[0m My awesome story name",
+				"",
+			}))
+		})
+
+		It("logs the commit with ⧗ when the story was accepted too recently", func() {
+			vl.Commit(&git.Commit{
+				Hash:      "ABC123DEF456",
+				Subject:   "Update bumper to be awesome",
+				StoryID:   12345678,
+				StoryName: "My awesome story name",
+				Soaking:   true,
+			})
+			Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+				"\033[33m⧗\033[0m \033[33mABC123DE\033[0m Update bumper to be awesome              \033[34m12345678\033[0m My awesome story name",
+				"",
+			}))
+		})
+
+		It("logs the commit with ❄ when the commit is held back by a freeze", func() {
+			vl.Commit(&git.Commit{
+				Hash:      "ABC123DEF456",
+				Subject:   "Update bumper to be awesome",
+				StoryID:   12345678,
+				StoryName: "My awesome story name",
+				Frozen:    true,
+			})
+			Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+				"\033[34m❄\033[0m \033[33mABC123DE\033[0m Update bumper to be awesome              \033[34m12345678\033[0m My awesome story name",
+				"",
+			}))
+		})
+
+		It("logs the commit with ⚠ when the commit was skipped for CI", func() {
+			vl.Commit(&git.Commit{
+				Hash:      "ABC123DEF456",
+				Subject:   "Update bumper to be awesome",
+				StoryID:   12345678,
+				StoryName: "My awesome story name",
+				Accepted:  true,
+				CIFailed:  true,
+			})
+			Expect(strings.Split(buf.String(), "\n")).To(Equal([]string{
+				"\033[202m⚠\033[0m \033[33mABC123DE\033[0m Update bumper to be awesome              \033[34m12345678\033[0m My awesome story name",
+				"",
+			}))
+		})
 	})
 
 	It("does not print color if color is disabled", func() {
@@ -84,8 +164,6 @@ var _ = Describe("VerboseLogger", func() {
 			logger.WithColorDisabled(),
 		)
 
-
-
 		vl.Header("master..release-elect")
 		vl.Commit(&git.Commit{
 			Hash:     "ABC123DEF456",
@@ -0,0 +1,247 @@
+package multi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+)
+
+// Bumper computes the bump of a single repository.
+type Bumper interface {
+	Bump() (bumper.Result, error)
+}
+
+// Repo is a repository to bump.
+type Repo struct {
+	Name   string
+	Path   string
+	Bumper Bumper
+}
+
+// Result is the bump of a single repository.
+type Result struct {
+	Name   string
+	Path   string
+	Result bumper.Result
+	Err    error
+
+	// HeldStories are stories whose commits in this repository were held
+	// back by CompleteStories.
+	HeldStories []int
+}
+
+// Run computes the bumps of all repos concurrently. Results are returned in
+// the same order as the repos.
+func Run(repos []Repo) []Result {
+	results := make([]Result, len(repos))
+
+	var wg sync.WaitGroup
+	for i, r := range repos {
+		wg.Add(1)
+		go func(i int, r Repo) {
+			defer wg.Done()
+
+			res, err := r.Bumper.Bump()
+			results[i] = Result{
+				Name:   r.Name,
+				Path:   r.Path,
+				Result: res,
+				Err:    err,
+			}
+		}(i, r)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// CompleteStories holds back the commits of a story in every repository
+// unless all repositories can bump past all of that story's commits. When a
+// repository failed to bump its stories are unknown, so every story is held
+// back.
+func CompleteStories(results []Result) []Result {
+	completed := make([]Result, len(results))
+	copy(completed, results)
+
+	held := make(map[int]bool)
+	if Failed(completed) {
+		holdAll(held, completed)
+	}
+	for {
+		changed := false
+		for i, r := range completed {
+			if r.Err != nil {
+				continue
+			}
+
+			res, err := results[i].Result.Hold(held)
+			if err != nil {
+				completed[i].Err = err
+				holdAll(held, completed)
+				changed = true
+				continue
+			}
+			completed[i].Result = res
+			for _, c := range completed[i].Result.Remaining() {
+				if c.StoryID != 0 && !held[c.StoryID] {
+					held[c.StoryID] = true
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	for i, r := range completed {
+		if r.Err != nil {
+			continue
+		}
+
+		completed[i].HeldStories = heldStories(results[i].Result, completed[i].Result, held)
+	}
+
+	return completed
+}
+
+// holdAll holds every story of the results.
+func holdAll(held map[int]bool, results []Result) {
+	for _, r := range results {
+		for _, c := range r.Result.Commits {
+			for _, mc := range c.WithMerged() {
+				if mc.StoryID != 0 {
+					held[mc.StoryID] = true
+				}
+			}
+		}
+	}
+}
+
+// heldStories returns the held stories that have commits in the completed
+// bump's remaining commits but not in the original bump's.
+func heldStories(original, completed bumper.Result, held map[int]bool) []int {
+	wasRemaining := make(map[string]bool)
+	for _, c := range original.Remaining() {
+		wasRemaining[c.Hash] = true
+	}
+
+	var stories []int
+	seen := make(map[int]bool)
+	for _, c := range completed.Remaining() {
+		if wasRemaining[c.Hash] || !held[c.StoryID] || seen[c.StoryID] {
+			continue
+		}
+		seen[c.StoryID] = true
+		stories = append(stories, c.StoryID)
+	}
+
+	sort.Ints(stories)
+	return stories
+}
+
+// Failed reports whether computing any of the bumps failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Status combines the bumps of the repositories: FullBump if every
+// repository bumps its whole range, NothingToBump if none bumps anything and
+// PartialBump otherwise.
+func Status(results []Result) bumper.Status {
+	full, nothing := true, true
+	for _, r := range results {
+		switch r.Result.Status() {
+		case bumper.FullBump:
+			nothing = false
+		case bumper.PartialBump:
+			full, nothing = false, false
+		default:
+			full = false
+		}
+	}
+
+	switch {
+	case full:
+		return bumper.FullBump
+	case nothing:
+		return bumper.NothingToBump
+	default:
+		return bumper.PartialBump
+	}
+}
+
+// WriteTable writes the results as a table with a row per repository.
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tRANGE\tCOMMITS\tBUMP SHA")
+
+	for _, r := range results {
+		bumpSHA := r.Result.BumpSHA
+		switch {
+		case r.Err != nil:
+			bumpSHA = "error: " + r.Err.Error()
+		case bumpSHA == "":
+			bumpSHA = "-"
+		}
+
+		fmt.Fprintf(
+			tw,
+			"%s\t%s\t%d\t%s\n",
+			r.Name,
+			r.Result.CommitRange,
+			len(r.Result.Commits),
+			bumpSHA,
+		)
+	}
+
+	return tw.Flush()
+}
+
+type jsonResult struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	CommitRange string `json:"commit_range"`
+	Commits     int    `json:"commits"`
+	BumpSHA     string `json:"bump_sha"`
+	HeldStories []int  `json:"held_stories,omitempty"`
+	Freeze      string `json:"freeze,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// WriteJSON writes the results as a JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	out := make([]jsonResult, 0, len(results))
+	for _, r := range results {
+		jr := jsonResult{
+			Name:        r.Name,
+			Path:        r.Path,
+			CommitRange: r.Result.CommitRange,
+			Commits:     len(r.Result.Commits),
+			BumpSHA:     r.Result.BumpSHA,
+			HeldStories: r.HeldStories,
+		}
+		if r.Result.Freeze != nil {
+			jr.Freeze = r.Result.Freeze.Name
+		}
+		if r.Err != nil {
+			jr.Error = r.Err.Error()
+		}
+		out = append(out, jr)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
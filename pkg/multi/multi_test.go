@@ -0,0 +1,237 @@
+package multi_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/multi"
+)
+
+var _ = Describe("Multi", func() {
+	var repos []multi.Repo
+
+	BeforeEach(func() {
+		repos = []multi.Repo{
+			{
+				Name: "loggregator-release",
+				Path: "/repos/loggregator-release",
+				Bumper: &stubBumper{
+					result: bumper.Result{
+						CommitRange: "master..release-elect",
+						Commits:     []*git.Commit{{Hash: "abc123"}, {Hash: "def456"}},
+						BumpSHA:     "abc123",
+					},
+				},
+			},
+			{
+				Name: "loggregator-agent-release",
+				Path: "/repos/loggregator-agent-release",
+				Bumper: &stubBumper{
+					result: bumper.Result{
+						CommitRange: "main..release-elect",
+						Freeze:      &freeze.Window{Name: "holidays"},
+					},
+				},
+			},
+			{
+				Name: "broken-release",
+				Path: "/repos/broken-release",
+				Bumper: &stubBumper{
+					err: errors.New("bad revision"),
+				},
+			},
+		}
+	})
+
+	It("bumps every repo and keeps their order", func() {
+		results := multi.Run(repos)
+
+		Expect(results).To(HaveLen(3))
+		Expect(results[0].Name).To(Equal("loggregator-release"))
+		Expect(results[0].Result.BumpSHA).To(Equal("abc123"))
+		Expect(results[1].Name).To(Equal("loggregator-agent-release"))
+		Expect(results[1].Result.BumpSHA).To(BeEmpty())
+		Expect(results[2].Err).To(MatchError("bad revision"))
+		Expect(multi.Failed(results)).To(BeTrue())
+	})
+
+	Describe("CompleteStories", func() {
+		It("holds back stories that can't be bumped in every repo", func() {
+			results := []multi.Result{
+				{
+					Name: "loggregator-release",
+					Result: bumper.Result{
+						Commits: []*git.Commit{
+							{Hash: "c3", StoryID: 3, Accepted: true},
+							{Hash: "c2", StoryID: 2, Accepted: true},
+							{Hash: "c1", StoryID: 1, Accepted: true},
+						},
+						BumpSHA: "c3",
+					},
+				},
+				{
+					Name: "loggregator-agent-release",
+					Result: bumper.Result{
+						Commits: []*git.Commit{
+							{Hash: "a3", StoryID: 2, Accepted: true},
+							{Hash: "a2", StoryID: 4, Accepted: false},
+							{Hash: "a1", StoryID: 1, Accepted: true},
+						},
+						BumpSHA: "a1",
+					},
+				},
+			}
+
+			completed := multi.CompleteStories(results)
+
+			Expect(completed[0].Result.BumpSHA).To(Equal("c1"))
+			Expect(completed[0].HeldStories).To(Equal([]int{2, 3}))
+			Expect(completed[1].Result.BumpSHA).To(Equal("a1"))
+			Expect(completed[1].HeldStories).To(BeEmpty())
+
+			Expect(results[0].Result.BumpSHA).To(Equal("c3"))
+		})
+
+		It("holds back every story when a repo failed", func() {
+			results := []multi.Result{
+				{
+					Name: "loggregator-release",
+					Result: bumper.Result{
+						Commits: []*git.Commit{
+							{Hash: "c3", StoryID: 3, Accepted: true},
+							{Hash: "c2", Accepted: true},
+							{Hash: "c1", StoryID: 1, Accepted: true},
+						},
+						BumpSHA: "c3",
+					},
+				},
+				{
+					Name: "broken-release",
+					Err:  errors.New("bad revision"),
+				},
+			}
+
+			completed := multi.CompleteStories(results)
+
+			Expect(completed[0].Result.BumpSHA).To(BeEmpty())
+			Expect(completed[0].HeldStories).To(Equal([]int{1, 3}))
+			Expect(completed[1].Err).To(HaveOccurred())
+		})
+
+		It("leaves bumps alone when every story can be completed", func() {
+			results := []multi.Result{
+				{
+					Result: bumper.Result{
+						Commits: []*git.Commit{
+							{Hash: "c2", StoryID: 2, Accepted: true},
+							{Hash: "c1", StoryID: 1, Accepted: true},
+						},
+						BumpSHA: "c2",
+					},
+				},
+				{
+					Result: bumper.Result{
+						Commits: []*git.Commit{
+							{Hash: "a1", StoryID: 1, Accepted: true},
+						},
+						BumpSHA: "a1",
+					},
+				},
+			}
+
+			completed := multi.CompleteStories(results)
+
+			Expect(completed[0].Result.BumpSHA).To(Equal("c2"))
+			Expect(completed[1].Result.BumpSHA).To(Equal("a1"))
+		})
+	})
+
+	Describe("Status", func() {
+		bumped := func(commits []*git.Commit, bumpSHA string) multi.Result {
+			return multi.Result{Result: bumper.Result{Commits: commits, BumpSHA: bumpSHA}}
+		}
+		commits := []*git.Commit{{Hash: "abc123"}, {Hash: "def456"}}
+
+		It("is a full bump when every repo bumps its whole range", func() {
+			Expect(multi.Status([]multi.Result{
+				bumped(commits, "abc123"),
+				bumped(commits, "abc123"),
+			})).To(Equal(bumper.FullBump))
+		})
+
+		It("is nothing to bump when no repo can bump", func() {
+			Expect(multi.Status([]multi.Result{
+				bumped(commits, ""),
+				bumped(nil, ""),
+			})).To(Equal(bumper.NothingToBump))
+		})
+
+		It("is a partial bump when only part of the repos can bump", func() {
+			Expect(multi.Status([]multi.Result{
+				bumped(commits, "abc123"),
+				bumped(commits, ""),
+			})).To(Equal(bumper.PartialBump))
+			Expect(multi.Status([]multi.Result{
+				bumped(commits, "def456"),
+			})).To(Equal(bumper.PartialBump))
+		})
+	})
+
+	It("writes a table", func() {
+		buf := bytes.NewBuffer(nil)
+		Expect(multi.WriteTable(buf, multi.Run(repos))).To(Succeed())
+
+		Expect(buf.String()).To(Equal(
+			"REPO                       RANGE                  COMMITS  BUMP SHA\n" +
+				"loggregator-release        master..release-elect  2        abc123\n" +
+				"loggregator-agent-release  main..release-elect    0        -\n" +
+				"broken-release                                    0        error: bad revision\n",
+		))
+	})
+
+	It("writes JSON", func() {
+		buf := bytes.NewBuffer(nil)
+		Expect(multi.WriteJSON(buf, multi.Run(repos))).To(Succeed())
+
+		Expect(buf.String()).To(MatchJSON(`[
+			{
+				"name": "loggregator-release",
+				"path": "/repos/loggregator-release",
+				"commit_range": "master..release-elect",
+				"commits": 2,
+				"bump_sha": "abc123"
+			},
+			{
+				"name": "loggregator-agent-release",
+				"path": "/repos/loggregator-agent-release",
+				"commit_range": "main..release-elect",
+				"commits": 0,
+				"bump_sha": "",
+				"freeze": "holidays"
+			},
+			{
+				"name": "broken-release",
+				"path": "/repos/broken-release",
+				"commit_range": "",
+				"commits": 0,
+				"bump_sha": "",
+				"error": "bad revision"
+			}
+		]`))
+	})
+})
+
+type stubBumper struct {
+	result bumper.Result
+	err    error
+}
+
+func (s *stubBumper) Bump() (bumper.Result, error) {
+	return s.result, s.err
+}
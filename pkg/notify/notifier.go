@@ -0,0 +1,254 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+const (
+	// KindBump is sent when the bump SHA advances.
+	KindBump = "bump"
+	// KindBlocked is sent when a story has blocked the bump for too long.
+	KindBlocked = "blocked"
+
+	DefaultBumpTemplate    = `Bump possible for {{.CommitRange}}: {{.BumpSHA}} ({{len .Commits}} commits)`
+	DefaultBlockedTemplate = `Story #{{.Story.ID}} {{.Story.Name}} has blocked {{.CommitRange}} for {{.BlockedFor}}`
+)
+
+// Story is a story that blocks the bump.
+type Story struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Notification is a message sent to the sinks.
+type Notification struct {
+	Kind        string        `json:"kind"`
+	Text        string        `json:"text"`
+	CommitRange string        `json:"commit_range"`
+	BumpSHA     string        `json:"bump_sha"`
+	Commits     []*git.Commit `json:"-"`
+	Story       *Story        `json:"story,omitempty"`
+	BlockedFor  time.Duration `json:"blocked_for,omitempty"`
+}
+
+// Sink delivers notifications.
+type Sink interface {
+	Send(n Notification) error
+}
+
+// Notifier sends notifications about bump results to sinks. It keeps state
+// so the same bump or blocked story isn't announced twice.
+type Notifier struct {
+	sinks        []Sink
+	store        Store
+	blockedAfter time.Duration
+	templates    map[string]*template.Template
+	now          func() time.Time
+
+	commitRange string
+	commits     []*git.Commit
+}
+
+func New(sinks []Sink, opts ...NotifierOption) (*Notifier, error) {
+	n := &Notifier{
+		sinks:        sinks,
+		store:        &MemoryStore{},
+		blockedAfter: 72 * time.Hour,
+		templates:    make(map[string]*template.Template),
+		now:          time.Now,
+	}
+
+	defaults := map[string]string{
+		KindBump:    DefaultBumpTemplate,
+		KindBlocked: DefaultBlockedTemplate,
+	}
+	for kind, text := range defaults {
+		err := n.setTemplate(kind, text)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range opts {
+		err := o(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// Notify announces a new bump SHA and stories that have blocked the bump for
+// longer than the threshold. The state is saved only if every notification
+// is sent, so failed notifications are retried.
+func (n *Notifier) Notify(r bumper.Result) error {
+	loaded, err := n.store.Load()
+	if err != nil {
+		return err
+	}
+	// the state is only changed once every notification is sent
+	s := loaded.copy()
+
+	var notifications []Notification
+	if r.BumpSHA != "" && r.BumpSHA != s.BumpSHA {
+		notifications = append(notifications, Notification{
+			Kind:        KindBump,
+			CommitRange: r.CommitRange,
+			BumpSHA:     r.BumpSHA,
+			Commits:     r.Bumpable(),
+		})
+	}
+	s.BumpSHA = r.BumpSHA
+
+	now := n.now()
+	blocking := make(map[int]bool)
+	for _, story := range blockingStories(r) {
+		blocking[story.ID] = true
+
+		since, ok := s.BlockedSince[story.ID]
+		if !ok {
+			since = now
+			s.BlockedSince[story.ID] = now
+		}
+
+		blockedFor := now.Sub(since)
+		if blockedFor < n.blockedAfter || s.Announced[story.ID] {
+			continue
+		}
+		s.Announced[story.ID] = true
+
+		story := story
+		notifications = append(notifications, Notification{
+			Kind:        KindBlocked,
+			CommitRange: r.CommitRange,
+			BumpSHA:     r.BumpSHA,
+			Commits:     r.Bumpable(),
+			Story:       &story,
+			BlockedFor:  blockedFor.Round(time.Minute),
+		})
+	}
+
+	for id := range s.BlockedSince {
+		if !blocking[id] {
+			delete(s.BlockedSince, id)
+			delete(s.Announced, id)
+		}
+	}
+
+	for _, notification := range notifications {
+		err := n.send(notification)
+		if err != nil {
+			return err
+		}
+	}
+
+	return n.store.Save(s)
+}
+
+func (n *Notifier) send(notification Notification) error {
+	buf := bytes.NewBuffer(nil)
+	err := n.templates[notification.Kind].Execute(buf, notification)
+	if err != nil {
+		return fmt.Errorf("failed to render %s notification: %s", notification.Kind, err)
+	}
+	notification.Text = buf.String()
+
+	for _, s := range n.sinks {
+		err := s.Send(notification)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) setTemplate(kind, text string) error {
+	t, err := template.New(kind).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid %s template: %s", kind, err)
+	}
+	n.templates[kind] = t
+	return nil
+}
+
+// Header, Commit and Footer let the Notifier be used as a bumper.Logger.
+
+func (n *Notifier) Header(commitRange string) {
+	n.commitRange = commitRange
+}
+
+func (n *Notifier) Commit(c *git.Commit) {
+	n.commits = append(n.commits, c)
+}
+
+func (n *Notifier) Footer(bumpSHA string) {
+	err := n.Notify(bumper.Result{
+		CommitRange: n.commitRange,
+		Commits:     n.commits,
+		BumpSHA:     bumpSHA,
+	})
+	if err != nil {
+		log.Printf("failed to notify: %s", err)
+	}
+}
+
+func blockingStories(r bumper.Result) []Story {
+	var stories []Story
+	seen := make(map[int]bool)
+	for _, c := range r.Blockers() {
+		if c.StoryID == 0 || seen[c.StoryID] {
+			continue
+		}
+		seen[c.StoryID] = true
+		stories = append(stories, Story{ID: c.StoryID, Name: c.StoryName})
+	}
+
+	sort.Slice(stories, func(i, j int) bool {
+		return stories[i].ID < stories[j].ID
+	})
+	return stories
+}
+
+type NotifierOption func(*Notifier) error
+
+// WithStore persists the notifier state, e.g. between runs from cron.
+func WithStore(s Store) NotifierOption {
+	return func(n *Notifier) error {
+		n.store = s
+		return nil
+	}
+}
+
+// WithBlockedAfter sets how long a story may block the bump before it is
+// announced. Defaults to 72 hours.
+func WithBlockedAfter(d time.Duration) NotifierOption {
+	return func(n *Notifier) error {
+		n.blockedAfter = d
+		return nil
+	}
+}
+
+// WithTemplate overrides the text/template used for a kind of notification.
+// Templates are executed with the Notification.
+func WithTemplate(kind, text string) NotifierOption {
+	return func(n *Notifier) error {
+		return n.setTemplate(kind, text)
+	}
+}
+
+func WithClock(now func() time.Time) NotifierOption {
+	return func(n *Notifier) error {
+		n.now = now
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package notify_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/notify"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		sink   *spySink
+		now    time.Time
+		result bumper.Result
+		n      *notify.Notifier
+	)
+
+	BeforeEach(func() {
+		sink = &spySink{}
+		now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		result = bumper.Result{
+			CommitRange: "master..release-elect",
+			Commits: []*git.Commit{
+				{Hash: "def123", StoryID: 2, StoryName: "Two"},
+				{Hash: "789abc", StoryID: 1, StoryName: "One", Accepted: true},
+			},
+			BumpSHA: "789abc",
+		}
+
+		var err error
+		n, err = notify.New([]notify.Sink{sink},
+			notify.WithBlockedAfter(time.Hour),
+			notify.WithClock(func() time.Time { return now }),
+		)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("announces a new bump once", func() {
+		Expect(n.Notify(result)).To(Succeed())
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(HaveLen(1))
+		Expect(sink.sent[0].Kind).To(Equal(notify.KindBump))
+		Expect(sink.sent[0].Text).To(Equal("Bump possible for master..release-elect: 789abc (1 commits)"))
+	})
+
+	It("does not announce when there is nothing to bump", func() {
+		result.BumpSHA = ""
+
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(BeEmpty())
+	})
+
+	It("announces stories that have blocked for too long once", func() {
+		result.BumpSHA = ""
+		Expect(n.Notify(result)).To(Succeed())
+		Expect(sink.sent).To(BeEmpty())
+
+		now = now.Add(90 * time.Minute)
+		Expect(n.Notify(result)).To(Succeed())
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(HaveLen(1))
+		Expect(sink.sent[0].Kind).To(Equal(notify.KindBlocked))
+		Expect(sink.sent[0].Story).To(Equal(&notify.Story{ID: 2, Name: "Two"}))
+		Expect(sink.sent[0].Text).To(Equal("Story #2 Two has blocked master..release-elect for 1h30m0s"))
+	})
+
+	It("forgets stories that stop blocking", func() {
+		result.BumpSHA = ""
+		Expect(n.Notify(result)).To(Succeed())
+
+		result.Commits[0].Accepted = true
+		now = now.Add(90 * time.Minute)
+		Expect(n.Notify(result)).To(Succeed())
+
+		result.Commits[0].Accepted = false
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(BeEmpty())
+	})
+
+	It("uses custom templates", func() {
+		var err error
+		n, err = notify.New([]notify.Sink{sink},
+			notify.WithTemplate(notify.KindBump, "bump {{.BumpSHA}}"),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent[0].Text).To(Equal("bump 789abc"))
+	})
+
+	It("returns an error for invalid templates", func() {
+		_, err := notify.New(nil, notify.WithTemplate(notify.KindBump, "{{"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("returns an error if a sink fails", func() {
+		sink.err = errors.New("could not send")
+
+		Expect(n.Notify(result)).ToNot(Succeed())
+	})
+
+	It("retries notifications that failed to send", func() {
+		result.BumpSHA = ""
+		Expect(n.Notify(result)).To(Succeed())
+
+		now = now.Add(90 * time.Minute)
+		sink.err = errors.New("could not send")
+		Expect(n.Notify(result)).ToNot(Succeed())
+
+		sink.err = nil
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(HaveLen(1))
+		Expect(sink.sent[0].Kind).To(Equal(notify.KindBlocked))
+	})
+
+	It("persists the state in a file", func() {
+		dir, err := ioutil.TempDir("", "bumper-notify")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		store := notify.NewFileStore(filepath.Join(dir, "state.json"))
+		n, err = notify.New([]notify.Sink{sink}, notify.WithStore(store))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n.Notify(result)).To(Succeed())
+
+		n, err = notify.New([]notify.Sink{sink}, notify.WithStore(store))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(n.Notify(result)).To(Succeed())
+
+		Expect(sink.sent).To(HaveLen(1))
+	})
+
+	It("can be used as a logger", func() {
+		n.Header(result.CommitRange)
+		for _, c := range result.Commits {
+			n.Commit(c)
+		}
+		n.Footer(result.BumpSHA)
+
+		Expect(sink.sent).To(HaveLen(1))
+		Expect(sink.sent[0].BumpSHA).To(Equal("789abc"))
+	})
+})
+
+type spySink struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (s *spySink) Send(n notify.Notification) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
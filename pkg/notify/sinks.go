@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+)
+
+// HTTPClient posts notifications to webhooks.
+type HTTPClient interface {
+	Post(url, contentType string, body io.Reader) (*http.Response, error)
+}
+
+// SlackSink posts notifications to a Slack-compatible incoming webhook.
+type SlackSink struct {
+	url        string
+	httpClient HTTPClient
+}
+
+func NewSlackSink(url string, httpClient HTTPClient) SlackSink {
+	return SlackSink{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+func (s SlackSink) Send(n Notification) error {
+	return postJSON(s.httpClient, s.url, map[string]string{"text": n.Text})
+}
+
+// WebhookSink posts notifications as JSON to a generic webhook.
+type WebhookSink struct {
+	url        string
+	httpClient HTTPClient
+}
+
+func NewWebhookSink(url string, httpClient HTTPClient) WebhookSink {
+	return WebhookSink{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+func (s WebhookSink) Send(n Notification) error {
+	return postJSON(s.httpClient, s.url, n)
+}
+
+func postJSON(httpClient HTTPClient, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code posting notification: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SendMailFunc sends an email, see smtp.SendMail.
+type SendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// EmailSink sends notifications by email over SMTP.
+type EmailSink struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	to       []string
+	sendMail SendMailFunc
+}
+
+func NewEmailSink(addr string, auth smtp.Auth, from string, to []string, opts ...EmailSinkOption) EmailSink {
+	s := EmailSink{
+		addr:     addr,
+		auth:     auth,
+		from:     from,
+		to:       to,
+		sendMail: smtp.SendMail,
+	}
+
+	for _, o := range opts {
+		o(&s)
+	}
+
+	return s
+}
+
+func (s EmailSink) Send(n Notification) error {
+	subject := n.Text
+	if i := strings.IndexByte(subject, '\n'); i >= 0 {
+		subject = subject[:i]
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		s.from,
+		strings.Join(s.to, ", "),
+		subject,
+		n.Text,
+	)
+
+	return s.sendMail(s.addr, s.auth, s.from, s.to, []byte(msg))
+}
+
+type EmailSinkOption func(*EmailSink)
+
+func WithSendMail(f SendMailFunc) EmailSinkOption {
+	return func(s *EmailSink) {
+		s.sendMail = f
+	}
+}
@@ -0,0 +1,111 @@
+package notify_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/notify"
+)
+
+var _ = Describe("Sinks", func() {
+	var (
+		requests []string
+		code     int
+		ts       *httptest.Server
+		n        notify.Notification
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		code = http.StatusOK
+		ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, string(body))
+			w.WriteHeader(code)
+		}))
+
+		n = notify.Notification{
+			Kind:        notify.KindBump,
+			Text:        "Bump possible",
+			CommitRange: "master..release-elect",
+			BumpSHA:     "789abc",
+		}
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	Describe("SlackSink", func() {
+		It("posts the text", func() {
+			s := notify.NewSlackSink(ts.URL, http.DefaultClient)
+
+			Expect(s.Send(n)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0]).To(MatchJSON(`{"text": "Bump possible"}`))
+		})
+
+		It("returns an error for unsuccessful responses", func() {
+			code = http.StatusInternalServerError
+			s := notify.NewSlackSink(ts.URL, http.DefaultClient)
+
+			Expect(s.Send(n)).ToNot(Succeed())
+		})
+	})
+
+	Describe("WebhookSink", func() {
+		It("posts the notification", func() {
+			s := notify.NewWebhookSink(ts.URL, http.DefaultClient)
+
+			Expect(s.Send(n)).To(Succeed())
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0]).To(MatchJSON(`{
+				"kind": "bump",
+				"text": "Bump possible",
+				"commit_range": "master..release-elect",
+				"bump_sha": "789abc"
+			}`))
+		})
+	})
+
+	Describe("EmailSink", func() {
+		It("sends an email", func() {
+			var (
+				addr string
+				from string
+				to   []string
+				msg  string
+			)
+			s := notify.NewEmailSink(
+				"smtp.example.com:587",
+				nil,
+				"bumper@example.com",
+				[]string{"team@example.com", "pm@example.com"},
+				notify.WithSendMail(func(a string, _ smtp.Auth, f string, t []string, m []byte) error {
+					addr, from, to, msg = a, f, t, string(m)
+					return nil
+				}),
+			)
+
+			Expect(s.Send(n)).To(Succeed())
+
+			Expect(addr).To(Equal("smtp.example.com:587"))
+			Expect(from).To(Equal("bumper@example.com"))
+			Expect(to).To(Equal([]string{"team@example.com", "pm@example.com"}))
+			Expect(msg).To(Equal(
+				"From: bumper@example.com\r\n" +
+					"To: team@example.com, pm@example.com\r\n" +
+					"Subject: Bump possible\r\n" +
+					"\r\n" +
+					"Bump possible\r\n",
+			))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// State is what has been announced so far.
+type State struct {
+	BumpSHA      string            `json:"bump_sha"`
+	BlockedSince map[int]time.Time `json:"blocked_since"`
+	Announced    map[int]bool      `json:"announced"`
+}
+
+// copy returns a copy of the state that does not share its maps.
+func (s State) copy() State {
+	c := State{
+		BumpSHA:      s.BumpSHA,
+		BlockedSince: make(map[int]time.Time),
+		Announced:    make(map[int]bool),
+	}
+	for id, t := range s.BlockedSince {
+		c.BlockedSince[id] = t
+	}
+	for id, a := range s.Announced {
+		c.Announced[id] = a
+	}
+	return c
+}
+
+// Store persists the notifier state.
+type Store interface {
+	Load() (State, error)
+	Save(State) error
+}
+
+// MemoryStore keeps the state for the lifetime of the process.
+type MemoryStore struct {
+	state State
+}
+
+func (s *MemoryStore) Load() (State, error) {
+	return s.state, nil
+}
+
+func (s *MemoryStore) Save(state State) error {
+	s.state = state
+	return nil
+}
+
+// FileStore keeps the state in a JSON file.
+type FileStore struct {
+	path string
+}
+
+func NewFileStore(path string) FileStore {
+	return FileStore{
+		path: path,
+	}
+}
+
+func (s FileStore) Load() (State, error) {
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, err
+	}
+
+	var state State
+	err = json.Unmarshal(b, &state)
+	return state, err
+}
+
+func (s FileStore) Save(state State) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.path, b, 0644)
+}
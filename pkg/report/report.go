@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+)
+
+const storyURLTemplate = "https://www.pivotaltracker.com/story/show/%d"
+
+// Markdown renders the result as Markdown with a table of the commits in the
+// range and links to their stories.
+func Markdown(r bumper.Result) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "## Bump of `%s`\n\n", r.CommitRange)
+
+	if r.Freeze != nil {
+		fmt.Fprintf(b, "> **Release freeze %s is active.**", escape(r.Freeze.Name))
+		if len(r.Freeze.AllowLabels) > 0 {
+			fmt.Fprintf(b, " Only stories labeled %s are bumped.", strings.Join(r.Freeze.AllowLabels, ", "))
+		}
+		fmt.Fprint(b, "\n\n")
+	}
+
+	if r.BumpSHA == "" {
+		fmt.Fprint(b, "There are no commits to bump!\n\n")
+	} else {
+		fmt.Fprintf(b, "This is the commit you should bump to: `%s`\n\n", r.BumpSHA)
+	}
+
+	if len(r.Commits) == 0 {
+		return b.String()
+	}
+
+	fmt.Fprint(b, "| | Commit | Subject | Story |\n")
+	fmt.Fprint(b, "|---|---|---|---|\n")
+	for _, c := range r.Commits {
+		for _, mc := range c.WithMerged() {
+			mark := "✗"
+			switch {
+			case mc.Reverted:
+				mark = "↺"
+			case mc.CIFailed:
+				mark = "⚠"
+			case mc.Soaking:
+				mark = "⧗"
+			case mc.Frozen:
+				mark = "❄"
+			case mc.Accepted || mc.StoryID == 0:
+				mark = "✓"
+			}
+
+			story := ""
+			if mc.StoryID != 0 {
+				story = storyLink(mc.StoryID, mc.StoryName)
+			}
+
+			fmt.Fprintf(
+				b,
+				"| %s | `%s` | %s | %s |\n",
+				mark,
+				mc.ShortSHA(),
+				escape(strings.TrimSpace(mc.Subject)),
+				story,
+			)
+		}
+	}
+	fmt.Fprintln(b)
+
+	return b.String()
+}
+
+// Stories renders a Markdown list of the stories that are part of the bump.
+func Stories(r bumper.Result) string {
+	b := &strings.Builder{}
+	seen := make(map[int]bool)
+	for _, c := range r.Bumpable() {
+		if c.StoryID == 0 || seen[c.StoryID] {
+			continue
+		}
+		seen[c.StoryID] = true
+
+		fmt.Fprintf(b, "- %s\n", storyLink(c.StoryID, c.StoryName))
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+
+	return "### Stories\n\n" + b.String() + "\n"
+}
+
+func storyLink(id int, name string) string {
+	link := fmt.Sprintf("[#%d]("+storyURLTemplate+")", id, id)
+	if name == "" {
+		return link
+	}
+
+	return link + " " + escape(name)
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,73 @@
+package report_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/report"
+)
+
+var _ = Describe("Report", func() {
+	var r bumper.Result
+
+	BeforeEach(func() {
+		r = bumper.Result{
+			CommitRange: "master..release-elect",
+			Commits: []*git.Commit{
+				{Hash: "DEF456ABC123", Subject: "Add drain", StoryID: 22222222, StoryName: "Drains"},
+				{Hash: "FED654CBA321", Subject: "Revert \"Add sink\"", StoryID: 33333333, Reverted: true},
+				{Hash: "ABC123DEF456", Subject: "Fix | pipe", StoryID: 11111111, StoryName: "Pipes", Accepted: true},
+				{Hash: "CBA321FED654", Subject: "Bump deps\n", Accepted: true},
+			},
+			BumpSHA: "ABC123DEF456",
+		}
+	})
+
+	Describe("Markdown", func() {
+		It("renders the commits with links to their stories", func() {
+			Expect(report.Markdown(r)).To(Equal(
+				"## Bump of `master..release-elect`\n\n" +
+					"This is the commit you should bump to: `ABC123DEF456`\n\n" +
+					"| | Commit | Subject | Story |\n" +
+					"|---|---|---|---|\n" +
+					"| ✗ | `DEF456AB` | Add drain | [#22222222](https://www.pivotaltracker.com/story/show/22222222) Drains |\n" +
+					"| ↺ | `FED654CB` | Revert \"Add sink\" | [#33333333](https://www.pivotaltracker.com/story/show/33333333) |\n" +
+					"| ✓ | `ABC123DE` | Fix \\| pipe | [#11111111](https://www.pivotaltracker.com/story/show/11111111) Pipes |\n" +
+					"| ✓ | `CBA321FE` | Bump deps |  |\n\n",
+			))
+		})
+
+		It("renders the active release freeze", func() {
+			r.Freeze = &freeze.Window{Name: "holidays", AllowLabels: []string{"hotfix"}}
+
+			Expect(report.Markdown(r)).To(HavePrefix(
+				"## Bump of `master..release-elect`\n\n" +
+					"> **Release freeze holidays is active.** Only stories labeled hotfix are bumped.\n\n",
+			))
+		})
+
+		It("renders that there is nothing to bump", func() {
+			Expect(report.Markdown(bumper.Result{CommitRange: "master..release-elect"})).To(Equal(
+				"## Bump of `master..release-elect`\n\nThere are no commits to bump!\n\n",
+			))
+		})
+	})
+
+	Describe("Stories", func() {
+		It("lists the stories that are part of the bump", func() {
+			Expect(report.Stories(r)).To(Equal(
+				"### Stories\n\n" +
+					"- [#11111111](https://www.pivotaltracker.com/story/show/11111111) Pipes\n\n",
+			))
+		})
+
+		It("is empty when no stories are part of the bump", func() {
+			r.BumpSHA = ""
+
+			Expect(report.Stories(r)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+// Bumper computes the bump of a range.
+type Bumper interface {
+	Bump() (bumper.Result, error)
+}
+
+// Cache caches story lookups across ranges. It is reset before all ranges
+// are recomputed and stories are invalidated when Tracker reports changes
+// to them.
+type Cache interface {
+	Reset()
+	Invalidate(storyIDs ...int)
+}
+
+// Fetcher updates the refs of a range before it is recomputed.
+type Fetcher interface {
+	Fetch() error
+}
+
+// Range is a named commit range whose bump status is served. The optional
+// Fetcher is called before each periodic recompute.
+type Range struct {
+	Name    string
+	Bumper  Bumper
+	Fetcher Fetcher
+}
+
+// Status is the most recently computed bump of a range.
+type Status struct {
+	Name        string         `json:"name"`
+	CommitRange string         `json:"commit_range"`
+	BumpSHA     string         `json:"bump_sha"`
+	Commits     []*git.Commit  `json:"commits"`
+	Blockers    []*git.Commit  `json:"blockers"`
+	Freeze      *freeze.Window `json:"freeze,omitempty"`
+	ComputedAt  time.Time      `json:"computed_at"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// Server serves the bump status of ranges over HTTP and recomputes them in
+// the background.
+type Server struct {
+	ranges       []Range
+	cache        Cache
+	interval     time.Duration
+	now          func() time.Time
+	webhookToken string
+
+	mu       sync.RWMutex
+	statuses map[string]Status
+
+	// locks serializes the recomputes of each range so that a slow
+	// recompute can't store its status over that of a later one.
+	locks map[string]*sync.Mutex
+
+	// pending are the ranges to recompute for webhooks, which a single
+	// worker recomputes whenever it is triggered.
+	pendingMu sync.Mutex
+	pending   map[string]bool
+	trigger   chan struct{}
+	worker    sync.Once
+}
+
+func New(ranges []Range, opts ...ServerOption) *Server {
+	s := &Server{
+		ranges:   ranges,
+		interval: 5 * time.Minute,
+		now:      time.Now,
+		statuses: make(map[string]Status),
+		locks:    make(map[string]*sync.Mutex),
+		pending:  make(map[string]bool),
+		trigger:  make(chan struct{}, 1),
+	}
+	for _, r := range ranges {
+		s.locks[r.Name] = &sync.Mutex{}
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s
+}
+
+// Run recomputes all ranges every interval until stop is closed.
+func (s *Server) Run(stop <-chan struct{}) {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+
+	for {
+		s.Recompute()
+
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Recompute resets the cache, fetches and computes the bump of every range.
+func (s *Server) Recompute() {
+	if s.cache != nil {
+		s.cache.Reset()
+	}
+
+	for _, r := range s.ranges {
+		s.recompute(r, true)
+	}
+}
+
+func (s *Server) recompute(r Range, fetch bool) {
+	l := s.locks[r.Name]
+	l.Lock()
+	defer l.Unlock()
+
+	if fetch && r.Fetcher != nil {
+		err := r.Fetcher.Fetch()
+		if err != nil {
+			s.store(r, bumper.Result{}, fmt.Errorf("failed to fetch: %s", err))
+			return
+		}
+	}
+
+	res, err := r.Bumper.Bump()
+	s.store(r, res, err)
+}
+
+func (s *Server) store(r Range, res bumper.Result, err error) {
+	status := Status{
+		Name:        r.Name,
+		CommitRange: res.CommitRange,
+		BumpSHA:     res.BumpSHA,
+		Commits:     res.Commits,
+		Blockers:    res.Blockers(),
+		Freeze:      res.Freeze,
+		ComputedAt:  s.now(),
+	}
+	if err != nil {
+		log.Printf("failed to compute bump of %s: %s", r.Name, err)
+		status.Error = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statuses[r.Name] = status
+}
+
+// Handler returns the HTTP handler of the server:
+//
+//	GET /health         reports that the server is up
+//	GET /ranges         lists the status of every range
+//	GET /ranges/<name>  returns the status of a single range
+//	POST /webhooks/tracker  accepts Tracker activity webhooks
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.health)
+	mux.HandleFunc("/ranges", s.listRanges)
+	mux.HandleFunc("/ranges/", s.getRange)
+	mux.HandleFunc("/webhooks/tracker", s.trackerWebhook)
+	return mux
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) listRanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(s.ranges))
+	for _, rng := range s.ranges {
+		status, ok := s.statuses[rng.Name]
+		if !ok {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) getRange(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/ranges/")
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status, ok := s.statuses[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown range "+name)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	err := json.NewEncoder(w).Encode(v)
+	if err != nil {
+		log.Printf("failed to write response: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+type ServerOption func(*Server)
+
+// WithCache resets the cache before the ranges are recomputed.
+func WithCache(c Cache) ServerOption {
+	return func(s *Server) {
+		s.cache = c
+	}
+}
+
+// WithWebhookToken requires Tracker webhooks to pass the token as the token
+// query parameter.
+func WithWebhookToken(token string) ServerOption {
+	return func(s *Server) {
+		s.webhookToken = token
+	}
+}
+
+func WithInterval(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.interval = d
+	}
+}
+
+func WithClock(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.now = now
+	}
+}
@@ -0,0 +1,275 @@
+package server_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/freeze"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/server"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		sc *spyCache
+		s  *server.Server
+		ts *httptest.Server
+	)
+
+	BeforeEach(func() {
+		sc = &spyCache{}
+		now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		s = server.New(
+			[]server.Range{
+				{
+					Name: "loggregator-release",
+					Bumper: &stubBumper{
+						result: bumper.Result{
+							CommitRange: "master..release-elect",
+							Commits: []*git.Commit{
+								{Hash: "def123", Subject: "Second", StoryID: 2, StoryName: "Two"},
+								{Hash: "789abc", Subject: "First", StoryID: 1, StoryName: "One", Accepted: true},
+							},
+							BumpSHA: "789abc",
+						},
+					},
+				},
+				{
+					Name: "broken-release",
+					Bumper: &stubBumper{
+						err: errors.New("bad revision"),
+					},
+				},
+			},
+			server.WithCache(sc),
+			server.WithClock(func() time.Time { return now }),
+		)
+		ts = httptest.NewServer(s.Handler())
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(ts.URL + path)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp.StatusCode, string(body)
+	}
+
+	It("reports health", func() {
+		code, body := get("/health")
+
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{"status": "ok"}`))
+	})
+
+	It("serves the status of a range", func() {
+		s.Recompute()
+
+		code, body := get("/ranges/loggregator-release")
+
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(MatchJSON(`{
+			"name": "loggregator-release",
+			"commit_range": "master..release-elect",
+			"bump_sha": "789abc",
+			"commits": [
+				{"sha": "def123", "subject": "Second", "story_id": 2, "story_name": "Two", "accepted": false},
+				{"sha": "789abc", "subject": "First", "story_id": 1, "story_name": "One", "accepted": true}
+			],
+			"blockers": [
+				{"sha": "def123", "subject": "Second", "story_id": 2, "story_name": "Two", "accepted": false}
+			],
+			"computed_at": "2020-01-02T03:04:05Z"
+		}`))
+	})
+
+	It("serves the active release freeze", func() {
+		s = server.New([]server.Range{
+			{
+				Name: "loggregator-release",
+				Bumper: &stubBumper{
+					result: bumper.Result{
+						CommitRange: "master..release-elect",
+						Freeze:      &freeze.Window{Name: "holidays", AllowLabels: []string{"hotfix"}},
+					},
+				},
+			},
+		})
+		s.Recompute()
+
+		ts.Close()
+		ts = httptest.NewServer(s.Handler())
+		_, body := get("/ranges/loggregator-release")
+
+		Expect(body).To(ContainSubstring(`"freeze":{"name":"holidays"`))
+		Expect(body).To(ContainSubstring(`"allow_labels":["hotfix"]`))
+	})
+
+	It("lists the status of every range", func() {
+		s.Recompute()
+
+		code, body := get("/ranges")
+
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring(`"name":"loggregator-release"`))
+		Expect(body).To(ContainSubstring(`"error":"bad revision"`))
+	})
+
+	It("returns not found for unknown ranges", func() {
+		s.Recompute()
+
+		code, _ := get("/ranges/unknown")
+
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+
+	It("returns not found for ranges that haven't been computed", func() {
+		code, _ := get("/ranges/loggregator-release")
+
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+
+	It("resets the cache before recomputing", func() {
+		s.Recompute()
+		s.Recompute()
+
+		Expect(sc.resets).To(Equal(2))
+	})
+
+	It("fetches the ranges before each periodic recompute", func() {
+		sb := &stubBumper{
+			result: bumper.Result{
+				Commits: []*git.Commit{{Hash: "789abc", Accepted: true}},
+				BumpSHA: "789abc",
+			},
+		}
+		sf := &stubFetcher{
+			bumper: sb,
+			fetched: bumper.Result{
+				Commits: []*git.Commit{
+					{Hash: "def123", Accepted: true},
+					{Hash: "789abc", Accepted: true},
+				},
+				BumpSHA: "def123",
+			},
+		}
+		s = server.New(
+			[]server.Range{{Name: "loggregator-release", Bumper: sb, Fetcher: sf}},
+			server.WithInterval(10*time.Millisecond),
+		)
+		ts.Close()
+		ts = httptest.NewServer(s.Handler())
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go s.Run(stop)
+
+		Eventually(func() string {
+			_, body := get("/ranges/loggregator-release")
+			return body
+		}).Should(ContainSubstring(`"bump_sha":"def123"`))
+	})
+
+	It("reports ranges that fail to fetch", func() {
+		s = server.New([]server.Range{
+			{
+				Name:    "loggregator-release",
+				Bumper:  &stubBumper{},
+				Fetcher: &stubFetcher{err: errors.New("could not resolve host")},
+			},
+		})
+		s.Recompute()
+
+		ts.Close()
+		ts = httptest.NewServer(s.Handler())
+		_, body := get("/ranges/loggregator-release")
+
+		Expect(body).To(ContainSubstring(`"error":"failed to fetch: could not resolve host"`))
+	})
+
+	It("rejects other methods", func() {
+		resp, err := http.Post(ts.URL+"/ranges", "application/json", nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
+
+type stubBumper struct {
+	mu     sync.Mutex
+	calls  int
+	result bumper.Result
+	err    error
+}
+
+func (s *stubBumper) Bump() (bumper.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.result, s.err
+}
+
+func (s *stubBumper) setResult(r bumper.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result = r
+}
+
+func (s *stubBumper) bumpCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// stubFetcher makes the bumper return the fetched result from the second
+// fetch on, like a new commit pushed after the first recompute.
+type stubFetcher struct {
+	bumper  *stubBumper
+	fetched bumper.Result
+	fetches int
+	err     error
+}
+
+func (s *stubFetcher) Fetch() error {
+	if s.err != nil {
+		return s.err
+	}
+	s.fetches++
+	if s.fetches > 1 {
+		s.bumper.setResult(s.fetched)
+	}
+	return nil
+}
+
+type spyCache struct {
+	mu          sync.Mutex
+	resets      int
+	invalidated []int
+}
+
+func (s *spyCache) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resets++
+}
+
+func (s *spyCache) Invalidate(storyIDs ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidated = append(s.invalidated, storyIDs...)
+}
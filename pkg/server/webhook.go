@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// activity is the subset of a Tracker activity webhook payload that
+// references stories.
+type activity struct {
+	Kind             string     `json:"kind"`
+	Changes          []resource `json:"changes"`
+	PrimaryResources []resource `json:"primary_resources"`
+}
+
+type resource struct {
+	Kind string `json:"kind"`
+	ID   int    `json:"id"`
+}
+
+func (a activity) storyIDs() []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, r := range append(a.PrimaryResources, a.Changes...) {
+		if r.Kind != "story" || r.ID == 0 || seen[r.ID] {
+			continue
+		}
+		seen[r.ID] = true
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// trackerWebhook accepts Tracker activity webhooks. The stories the activity
+// refers to are invalidated in the cache and the ranges containing them are
+// recomputed in the background.
+func (s *Server) trackerWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if s.webhookToken != "" && r.URL.Query().Get("token") != s.webhookToken {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	var a activity
+	err := json.NewDecoder(r.Body).Decode(&a)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid activity: "+err.Error())
+		return
+	}
+
+	ids := a.storyIDs()
+	if len(ids) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if s.cache != nil {
+		s.cache.Invalidate(ids...)
+	}
+
+	s.recomputeLater(s.rangesWithStories(ids))
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// recomputeLater marks the ranges to be recomputed by the worker. Ranges
+// marked again before the worker gets to them are recomputed once.
+func (s *Server) recomputeLater(ranges []Range) {
+	if len(ranges) == 0 {
+		return
+	}
+
+	s.pendingMu.Lock()
+	for _, rng := range ranges {
+		s.pending[rng.Name] = true
+	}
+	s.pendingMu.Unlock()
+
+	s.worker.Do(func() {
+		go s.recomputePending()
+	})
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Server) recomputePending() {
+	for range s.trigger {
+		s.pendingMu.Lock()
+		pending := s.pending
+		s.pending = make(map[string]bool)
+		s.pendingMu.Unlock()
+
+		for _, rng := range s.ranges {
+			if pending[rng.Name] {
+				s.recompute(rng, false)
+			}
+		}
+	}
+}
+
+// rangesWithStories returns the ranges whose last computed commits reference
+// any of the stories.
+func (s *Server) rangesWithStories(ids []int) []Range {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ranges []Range
+	for _, rng := range s.ranges {
+		if containsStory(s.statuses[rng.Name], ids) {
+			ranges = append(ranges, rng)
+		}
+	}
+	return ranges
+}
+
+func containsStory(status Status, ids []int) bool {
+	for _, c := range status.Commits {
+		for _, mc := range c.WithMerged() {
+			for _, id := range ids {
+				if mc.StoryID == id {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
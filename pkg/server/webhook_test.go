@@ -0,0 +1,214 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/server"
+)
+
+var _ = Describe("Tracker webhook", func() {
+	var (
+		sc           *spyCache
+		storyBumper  *stubBumper
+		otherBumper  *stubBumper
+		s            *server.Server
+		ts           *httptest.Server
+		acceptedBody string
+	)
+
+	BeforeEach(func() {
+		sc = &spyCache{}
+		storyBumper = &stubBumper{
+			result: bumper.Result{
+				Commits: []*git.Commit{
+					{Hash: "def123", StoryID: 2},
+					{Hash: "789abc", StoryID: 1, Accepted: true},
+				},
+				BumpSHA: "789abc",
+			},
+		}
+		otherBumper = &stubBumper{
+			result: bumper.Result{
+				Commits: []*git.Commit{
+					{Hash: "456789", StoryID: 3, Accepted: true},
+				},
+				BumpSHA: "456789",
+			},
+		}
+		s = server.New(
+			[]server.Range{
+				{Name: "loggregator-release", Bumper: storyBumper},
+				{Name: "other-release", Bumper: otherBumper},
+			},
+			server.WithCache(sc),
+			server.WithWebhookToken("secret"),
+		)
+		ts = httptest.NewServer(s.Handler())
+		s.Recompute()
+
+		acceptedBody = `{
+			"kind": "story_update_activity",
+			"highlight": "accepted",
+			"changes": [
+				{
+					"kind": "story",
+					"change_type": "update",
+					"id": 2,
+					"new_values": {"current_state": "accepted"}
+				}
+			],
+			"primary_resources": [
+				{"kind": "story", "id": 2, "name": "Two"}
+			]
+		}`
+	})
+
+	AfterEach(func() {
+		ts.Close()
+	})
+
+	post := func(path, body string) int {
+		resp, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("invalidates the stories and recomputes the ranges containing them", func() {
+		code := post("/webhooks/tracker?token=secret", acceptedBody)
+
+		Expect(code).To(Equal(http.StatusAccepted))
+		Expect(sc.invalidated).To(Equal([]int{2}))
+		Eventually(storyBumper.bumpCalls).Should(Equal(2))
+		Consistently(otherBumper.bumpCalls).Should(Equal(1))
+	})
+
+	It("stores the status of a webhook recompute over an earlier periodic one", func() {
+		gb := &gatedBumper{
+			results: []bumper.Result{
+				{Commits: []*git.Commit{{Hash: "def123", StoryID: 2}}},
+				{Commits: []*git.Commit{{Hash: "def123", StoryID: 2}}},
+				{Commits: []*git.Commit{{Hash: "def123", StoryID: 2, Accepted: true}}, BumpSHA: "def123"},
+			},
+			gates: map[int]chan struct{}{2: make(chan struct{})},
+		}
+		s = server.New([]server.Range{{Name: "loggregator-release", Bumper: gb}})
+		ts.Close()
+		ts = httptest.NewServer(s.Handler())
+		s.Recompute()
+
+		go s.Recompute()
+		Eventually(gb.bumpCalls).Should(Equal(2))
+
+		Expect(post("/webhooks/tracker", acceptedBody)).To(Equal(http.StatusAccepted))
+		Consistently(gb.bumpCalls).Should(Equal(2))
+
+		close(gb.gates[2])
+		Eventually(gb.bumpCalls).Should(Equal(3))
+		Eventually(func() string {
+			return getStatus(ts).BumpSHA
+		}).Should(Equal("def123"))
+	})
+
+	It("coalesces webhooks arriving during a recompute", func() {
+		gb := &gatedBumper{
+			results: []bumper.Result{
+				{Commits: []*git.Commit{{Hash: "def123", StoryID: 2}}},
+			},
+			gates: map[int]chan struct{}{2: make(chan struct{})},
+		}
+		s = server.New([]server.Range{{Name: "loggregator-release", Bumper: gb}})
+		ts.Close()
+		ts = httptest.NewServer(s.Handler())
+		s.Recompute()
+
+		Expect(post("/webhooks/tracker", acceptedBody)).To(Equal(http.StatusAccepted))
+		Eventually(gb.bumpCalls).Should(Equal(2))
+		for i := 0; i < 3; i++ {
+			Expect(post("/webhooks/tracker", acceptedBody)).To(Equal(http.StatusAccepted))
+		}
+
+		close(gb.gates[2])
+		Eventually(gb.bumpCalls).Should(Equal(3))
+		Consistently(gb.bumpCalls).Should(Equal(3))
+	})
+
+	It("ignores activity that doesn't reference stories", func() {
+		code := post("/webhooks/tracker?token=secret", `{"kind": "project_update_activity"}`)
+
+		Expect(code).To(Equal(http.StatusNoContent))
+		Expect(sc.invalidated).To(BeEmpty())
+	})
+
+	It("rejects requests without the token", func() {
+		code := post("/webhooks/tracker", acceptedBody)
+
+		Expect(code).To(Equal(http.StatusUnauthorized))
+		Expect(sc.invalidated).To(BeEmpty())
+	})
+
+	It("rejects invalid payloads", func() {
+		code := post("/webhooks/tracker?token=secret", `{`)
+
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("rejects other methods", func() {
+		resp, err := http.Get(ts.URL + "/webhooks/tracker?token=secret")
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
+
+func getStatus(ts *httptest.Server) server.Status {
+	resp, err := http.Get(ts.URL + "/ranges/loggregator-release")
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	var status server.Status
+	Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+	return status
+}
+
+// gatedBumper returns its results in turn, repeating the last one. Calls
+// with a gate block until it is closed.
+type gatedBumper struct {
+	mu      sync.Mutex
+	calls   int
+	results []bumper.Result
+	gates   map[int]chan struct{}
+}
+
+func (s *gatedBumper) Bump() (bumper.Result, error) {
+	s.mu.Lock()
+	s.calls++
+	n := s.calls
+	r := s.results[len(s.results)-1]
+	if n <= len(s.results) {
+		r = s.results[n-1]
+	}
+	gate := s.gates[n]
+	s.mu.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+	return r, nil
+}
+
+func (s *gatedBumper) bumpCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
@@ -3,20 +3,35 @@ package tracker
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"net/http"
+	"sync"
+	"time"
 )
 
 const urlTemplate = "https://www.pivotaltracker.com/services/v5/stories/%d"
 
+// Client looks up stories in Tracker. Stories are cached, and copies of a
+// Client share the same cache, so a Client may be used concurrently.
+// Concurrent lookups of the same story share a single request.
 type Client struct {
+	mu         *sync.Mutex
 	cache      map[int]story
+	inflight   map[int]*lookup
 	httpClient HTTPClient
 }
 
+// lookup is a request for a story that is in flight.
+type lookup struct {
+	done chan struct{}
+	s    story
+	err  error
+}
+
 func NewClient(options ...Option) Client {
 	c := Client{
+		mu:         &sync.Mutex{},
 		cache:      make(map[int]story),
+		inflight:   make(map[int]*lookup),
 		httpClient: http.DefaultClient,
 	}
 	for _, o := range options {
@@ -25,46 +40,141 @@ func NewClient(options ...Option) Client {
 	return c
 }
 
-func (c Client) IsAccepted(storyID int) bool {
+func (c Client) IsAccepted(storyID int) (bool, error) {
 	if storyID == 0 {
-		return true
+		return true, nil
+	}
+
+	s, err := c.story(storyID)
+	if err != nil {
+		return false, err
 	}
 
-	s := c.story(storyID)
+	return s.State == "accepted", nil
+}
+
+func (c Client) Name(storyID int) (string, error) {
+	if storyID == 0 {
+		return "", nil
+	}
+
+	s, err := c.story(storyID)
+	if err != nil {
+		return "", err
+	}
 
-	return s.State == "accepted"
+	return s.Name, nil
 }
 
-func (c Client) Name(storyID int) string {
+// AcceptedAt returns when the story was accepted, or the zero time if it
+// has not been accepted.
+func (c Client) AcceptedAt(storyID int) (time.Time, error) {
 	if storyID == 0 {
-		return ""
+		return time.Time{}, nil
 	}
 
-	s := c.story(storyID)
+	s, err := c.story(storyID)
+	if err != nil {
+		return time.Time{}, err
+	}
 
-	return s.Name
+	return s.AcceptedAt, nil
 }
 
-func (c Client) story(storyID int) story {
+// Labels returns the names of the story's labels.
+func (c Client) Labels(storyID int) ([]string, error) {
+	if storyID == 0 {
+		return nil, nil
+	}
+
+	s, err := c.story(storyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, l := range s.Labels {
+		names = append(names, l.Name)
+	}
+	return names, nil
+}
+
+// Reset clears the story cache so stories are looked up again.
+func (c Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id := range c.cache {
+		delete(c.cache, id)
+	}
+	for id := range c.inflight {
+		delete(c.inflight, id)
+	}
+}
+
+// Invalidate removes the given stories from the cache so they are looked up
+// again.
+func (c Client) Invalidate(storyIDs ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range storyIDs {
+		delete(c.cache, id)
+		delete(c.inflight, id)
+	}
+}
+
+func (c Client) story(storyID int) (story, error) {
+	c.mu.Lock()
 	s, ok := c.cache[storyID]
 	if ok {
-		return s
+		c.mu.Unlock()
+		return s, nil
 	}
+	l, ok := c.inflight[storyID]
+	if ok {
+		c.mu.Unlock()
+		<-l.done
+		return l.s, l.err
+	}
+	l = &lookup{done: make(chan struct{})}
+	c.inflight[storyID] = l
+	c.mu.Unlock()
+
+	l.s, l.err = c.get(storyID)
+
+	c.mu.Lock()
+	// the story may have been invalidated while it was looked up
+	if c.inflight[storyID] == l {
+		delete(c.inflight, storyID)
+		if l.err == nil {
+			c.cache[storyID] = l.s
+		}
+	}
+	c.mu.Unlock()
+	close(l.done)
 
+	return l.s, l.err
+}
+
+func (c Client) get(storyID int) (story, error) {
+	var s story
 	resp, err := c.httpClient.Get(fmt.Sprintf(urlTemplate, storyID))
 	if err != nil {
-		log.Fatalf("failed to get story: %s", err)
+		return story{}, fmt.Errorf("failed to get story %d: %s", storyID, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return story{}, fmt.Errorf("failed to get story %d: unexpected status code %d", storyID, resp.StatusCode)
+	}
+
 	err = json.NewDecoder(resp.Body).Decode(&s)
 	if err != nil {
-		log.Fatalf("failed to unmarshal story: %s", err)
+		return story{}, fmt.Errorf("failed to unmarshal story %d: %s", storyID, err)
 	}
 
-	c.cache[storyID] = s
-
-	return s
+	return s, nil
 }
 
 type HTTPClient interface {
@@ -82,4 +192,11 @@ func WithHTTPClient(hc HTTPClient) Option {
 type story struct {
 	State string `json:"current_state"`
 	Name  string `json:"name"`
+
+	AcceptedAt time.Time `json:"accepted_at"`
+	Labels     []label   `json:"labels"`
+}
+
+type label struct {
+	Name string `json:"name"`
 }
@@ -1,10 +1,13 @@
 package tracker_test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/loggregator/bumper/pkg/tracker"
 
@@ -25,7 +28,8 @@ var _ = Describe("Client", func() {
 				client := tracker.NewClient(
 					tracker.WithHTTPClient(shc),
 				)
-				accepted := client.IsAccepted(1)
+				accepted, err := client.IsAccepted(1)
+				Expect(err).ToNot(HaveOccurred())
 				Expect(accepted).To(BeTrue())
 				Expect(shc.getURLs).To(HaveLen(1))
 				Expect(shc.getURLs[0]).To(Equal("https://www.pivotaltracker.com/services/v5/stories/1"))
@@ -39,7 +43,8 @@ var _ = Describe("Client", func() {
 					tracker.WithHTTPClient(shc),
 				)
 
-				accepted := client.IsAccepted(0)
+				accepted, err := client.IsAccepted(0)
+				Expect(err).ToNot(HaveOccurred())
 				Expect(accepted).To(BeTrue())
 			})
 		})
@@ -55,7 +60,8 @@ var _ = Describe("Client", func() {
 				client := tracker.NewClient(
 					tracker.WithHTTPClient(shc),
 				)
-				accepted := client.IsAccepted(1)
+				accepted, err := client.IsAccepted(1)
+				Expect(err).ToNot(HaveOccurred())
 				Expect(accepted).To(BeFalse())
 				Expect(shc.getURLs).To(HaveLen(1))
 				Expect(shc.getURLs[0]).To(Equal("https://www.pivotaltracker.com/services/v5/stories/1"))
@@ -64,6 +70,63 @@ var _ = Describe("Client", func() {
 
 	})
 
+	Describe("AcceptedAt", func() {
+		It("returns when the story was accepted", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: `{"id": 1, "current_state": "accepted", "accepted_at": "2019-03-04T17:30:00Z"}`, code: 200},
+				},
+			}
+
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+			Expect(client.AcceptedAt(1)).To(Equal(time.Date(2019, 3, 4, 17, 30, 0, 0, time.UTC)))
+		})
+
+		It("returns the zero time when the story is not accepted", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: responseBody(1, "finished"), code: 200},
+				},
+			}
+
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+			Expect(client.AcceptedAt(1)).To(BeZero())
+		})
+
+		It("returns the zero time when story ID is 0", func() {
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(&stubHTTPClient{}),
+			)
+			Expect(client.AcceptedAt(0)).To(BeZero())
+		})
+	})
+
+	Describe("Labels", func() {
+		It("returns the names of the story's labels", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: `{"id": 1, "labels": [{"id": 5, "name": "hotfix"}, {"id": 6, "name": "logs"}]}`, code: 200},
+				},
+			}
+
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+			Expect(client.Labels(1)).To(Equal([]string{"hotfix", "logs"}))
+		})
+
+		It("returns no labels when story ID is 0", func() {
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(&stubHTTPClient{}),
+			)
+			Expect(client.Labels(0)).To(BeEmpty())
+		})
+	})
+
 	Describe("Name", func() {
 		It("returns the story name", func() {
 			shc := &stubHTTPClient{
@@ -90,6 +153,52 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("errors", func() {
+		It("returns an error when the request fails", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{err: errors.New("timeout")},
+				},
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+
+			_, err := client.IsAccepted(1)
+			Expect(err).To(MatchError("failed to get story 1: timeout"))
+		})
+
+		It("returns an error for unexpected status codes", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: `{"code": "unfound_resource"}`, code: 404},
+				},
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+
+			_, err := client.Name(1)
+			Expect(err).To(MatchError("failed to get story 1: unexpected status code 404"))
+		})
+
+		It("does not cache failed lookups", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: "not json", code: 200},
+					{body: responseBody(1, "accepted"), code: 200},
+				},
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+
+			_, err := client.IsAccepted(1)
+			Expect(err).To(HaveOccurred())
+			Expect(client.IsAccepted(1)).To(BeTrue())
+		})
+	})
+
 	Describe("Story caching", func() {
 		It("caches the story when IsAccepted called", func() {
 			shc := &stubHTTPClient{
@@ -122,6 +231,76 @@ var _ = Describe("Client", func() {
 
 			Expect(shc.getURLs).To(HaveLen(1))
 		})
+
+		It("looks up different stories concurrently and the same story once", func() {
+			bhc := &blockingHTTPClient{
+				release: make(chan struct{}),
+				started: make(chan string, 3),
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(bhc),
+			)
+
+			var wg sync.WaitGroup
+			for _, id := range []int{1, 2, 1} {
+				wg.Add(1)
+				go func(id int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					Expect(client.IsAccepted(id)).To(BeTrue())
+				}(id)
+			}
+
+			Eventually(bhc.started).Should(Receive())
+			Eventually(bhc.started).Should(Receive())
+			Consistently(bhc.started).ShouldNot(Receive())
+
+			close(bhc.release)
+			wg.Wait()
+		})
+
+		It("looks up stories again after the cache is reset", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: responseBody(1, "finished"), code: 200},
+					{body: responseBody(1, "accepted"), code: 200},
+				},
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+
+			Expect(client.IsAccepted(1)).To(BeFalse())
+			client.Reset()
+			Expect(client.IsAccepted(1)).To(BeTrue())
+
+			Expect(shc.getURLs).To(HaveLen(2))
+		})
+
+		It("looks up invalidated stories again", func() {
+			shc := &stubHTTPClient{
+				getResponses: []httpResponse{
+					{body: responseBody(1, "finished"), code: 200},
+					{body: responseBody(2, "finished"), code: 200},
+					{body: responseBody(1, "accepted"), code: 200},
+				},
+			}
+			client := tracker.NewClient(
+				tracker.WithHTTPClient(shc),
+			)
+
+			client.IsAccepted(1)
+			client.IsAccepted(2)
+			client.Invalidate(1)
+			client.IsAccepted(1)
+			client.IsAccepted(2)
+
+			Expect(shc.getURLs).To(Equal([]string{
+				"https://www.pivotaltracker.com/services/v5/stories/1",
+				"https://www.pivotaltracker.com/services/v5/stories/2",
+				"https://www.pivotaltracker.com/services/v5/stories/1",
+			}))
+		})
 	})
 })
 
@@ -150,6 +329,22 @@ func (s *stubHTTPClient) Get(url string) (*http.Response, error) {
 	}, nil
 }
 
+// blockingHTTPClient blocks requests until it is released.
+type blockingHTTPClient struct {
+	release chan struct{}
+	started chan string
+}
+
+func (b *blockingHTTPClient) Get(url string) (*http.Response, error) {
+	b.started <- url
+	<-b.release
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(responseBody(1, "accepted"))),
+	}, nil
+}
+
 func responseBody(storyID int, state string) string {
 	return fmt.Sprintf(responseBodyTemplate, storyID, state)
 }
@@ -0,0 +1,36 @@
+package tracker
+
+// StoryClient looks up the state of stories.
+type StoryClient interface {
+	IsAccepted(storyID int) (bool, error)
+	Name(storyID int) (string, error)
+}
+
+// OverrideClient overrides whether stories are accepted without changing
+// them in Tracker, e.g. to see what the bump would be if they were.
+type OverrideClient struct {
+	client   StoryClient
+	accepted map[int]bool
+}
+
+// NewOverrideClient returns a client that reports the stories in accepted
+// as accepted or not and asks client about all other stories.
+func NewOverrideClient(client StoryClient, accepted map[int]bool) OverrideClient {
+	return OverrideClient{
+		client:   client,
+		accepted: accepted,
+	}
+}
+
+func (c OverrideClient) IsAccepted(storyID int) (bool, error) {
+	accepted, ok := c.accepted[storyID]
+	if ok {
+		return accepted, nil
+	}
+
+	return c.client.IsAccepted(storyID)
+}
+
+func (c OverrideClient) Name(storyID int) (string, error) {
+	return c.client.Name(storyID)
+}
@@ -0,0 +1,56 @@
+package tracker_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/tracker"
+)
+
+var _ = Describe("OverrideClient", func() {
+	var (
+		sc *stubStoryClient
+		c  tracker.OverrideClient
+	)
+
+	BeforeEach(func() {
+		sc = &stubStoryClient{
+			accepted: map[int]bool{1: true, 2: false, 3: true},
+		}
+		c = tracker.NewOverrideClient(sc, map[int]bool{
+			2: true,
+			3: false,
+		})
+	})
+
+	It("returns the overridden states", func() {
+		Expect(c.IsAccepted(2)).To(BeTrue())
+		Expect(c.IsAccepted(3)).To(BeFalse())
+		Expect(sc.requests).To(BeEmpty())
+	})
+
+	It("asks the client about other stories", func() {
+		Expect(c.IsAccepted(1)).To(BeTrue())
+		Expect(sc.requests).To(Equal([]int{1}))
+	})
+
+	It("returns names from the client", func() {
+		Expect(c.Name(2)).To(Equal("Story 2"))
+	})
+})
+
+type stubStoryClient struct {
+	accepted map[int]bool
+	requests []int
+}
+
+func (s *stubStoryClient) IsAccepted(storyID int) (bool, error) {
+	s.requests = append(s.requests, storyID)
+	return s.accepted[storyID], nil
+}
+
+func (s *stubStoryClient) Name(storyID int) (string, error) {
+	return fmt.Sprintf("Story %d", storyID), nil
+}
@@ -0,0 +1,225 @@
+package watch
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+)
+
+// Bumper computes the bump that is watched.
+type Bumper interface {
+	Bump() (bumper.Result, error)
+}
+
+// Fetcher updates the refs used by the bump before each check.
+type Fetcher interface {
+	Fetch() error
+}
+
+// Cache caches story lookups and is reset before each check so that story
+// state changes are noticed.
+type Cache interface {
+	Reset()
+}
+
+// Story is a Tracker story referenced by the commits in the range.
+type Story struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Event describes how the bump changed since the previous check.
+type Event struct {
+	Time            time.Time `json:"time"`
+	BumpSHA         string    `json:"bump_sha"`
+	PreviousBumpSHA string    `json:"previous_bump_sha"`
+	// NewCommits are the SHAs of commits that became bumpable.
+	NewCommits []string `json:"new_commits"`
+	// Accepted are stories that were blocking and have been accepted.
+	Accepted []Story `json:"accepted"`
+	// NewBlockers are stories that started blocking the bump.
+	NewBlockers []Story `json:"new_blockers"`
+	// Blockers are all stories currently blocking the bump.
+	Blockers []Story `json:"blockers"`
+}
+
+// Watcher periodically recomputes the bump and emits an event when it
+// changes.
+type Watcher struct {
+	b        Bumper
+	f        Fetcher
+	cache    Cache
+	emit     func(Event)
+	interval time.Duration
+	now      func() time.Time
+
+	checked  bool
+	bumpSHA  string
+	bumpable map[string]bool
+	blockers map[int]Story
+}
+
+func New(b Bumper, emit func(Event), opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		b:        b,
+		emit:     emit,
+		interval: 5 * time.Minute,
+		now:      time.Now,
+	}
+
+	for _, o := range opts {
+		o(w)
+	}
+
+	return w
+}
+
+// Run checks the bump every interval until stop is closed. Errors are
+// logged and the watcher keeps going.
+func (w *Watcher) Run(stop <-chan struct{}) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	for {
+		err := w.Check()
+		if err != nil {
+			log.Printf("failed to check bump: %s", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Check fetches, recomputes the bump and emits an event if anything changed
+// since the previous check. The first check always emits an event.
+func (w *Watcher) Check() error {
+	if w.cache != nil {
+		w.cache.Reset()
+	}
+
+	if w.f != nil {
+		err := w.f.Fetch()
+		if err != nil {
+			return err
+		}
+	}
+
+	r, err := w.b.Bump()
+	if err != nil {
+		return err
+	}
+
+	bumpable := make(map[string]bool)
+	e := Event{
+		Time:            w.now(),
+		BumpSHA:         r.BumpSHA,
+		PreviousBumpSHA: w.bumpSHA,
+	}
+	for _, c := range r.Bumpable() {
+		bumpable[c.Hash] = true
+		if !w.bumpable[c.Hash] {
+			e.NewCommits = append(e.NewCommits, c.Hash)
+		}
+	}
+
+	blockers := stories(r.Blockers())
+	for _, s := range sortedStories(blockers) {
+		e.Blockers = append(e.Blockers, s)
+		if _, ok := w.blockers[s.ID]; !ok {
+			e.NewBlockers = append(e.NewBlockers, s)
+		}
+	}
+
+	accepted := stories(acceptedCommits(r.Commits))
+	for _, s := range sortedStories(w.blockers) {
+		if _, ok := accepted[s.ID]; ok {
+			e.Accepted = append(e.Accepted, s)
+		}
+	}
+
+	changed := !w.checked ||
+		r.BumpSHA != w.bumpSHA ||
+		len(e.NewCommits) > 0 ||
+		len(e.Accepted) > 0 ||
+		len(e.NewBlockers) > 0
+
+	w.checked = true
+	w.bumpSHA = r.BumpSHA
+	w.bumpable = bumpable
+	w.blockers = blockers
+
+	if changed {
+		w.emit(e)
+	}
+
+	return nil
+}
+
+func acceptedCommits(commits []*git.Commit) []*git.Commit {
+	var accepted []*git.Commit
+	for _, c := range commits {
+		for _, mc := range c.WithMerged() {
+			if mc.Accepted {
+				accepted = append(accepted, mc)
+			}
+		}
+	}
+	return accepted
+}
+
+func stories(commits []*git.Commit) map[int]Story {
+	s := make(map[int]Story)
+	for _, c := range commits {
+		if c.StoryID == 0 {
+			continue
+		}
+		s[c.StoryID] = Story{ID: c.StoryID, Name: c.StoryName}
+	}
+	return s
+}
+
+func sortedStories(stories map[int]Story) []Story {
+	var sorted []Story
+	for _, s := range stories {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+type WatcherOption func(*Watcher)
+
+// WithFetcher fetches before each check.
+func WithFetcher(f Fetcher) WatcherOption {
+	return func(w *Watcher) {
+		w.f = f
+	}
+}
+
+// WithCache resets the cache before each check.
+func WithCache(c Cache) WatcherOption {
+	return func(w *Watcher) {
+		w.cache = c
+	}
+}
+
+func WithInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+func WithClock(now func() time.Time) WatcherOption {
+	return func(w *Watcher) {
+		w.now = now
+	}
+}
@@ -0,0 +1,185 @@
+package watch_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/loggregator/bumper/pkg/bumper"
+	"github.com/loggregator/bumper/pkg/git"
+	"github.com/loggregator/bumper/pkg/watch"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		sb     *stubBumper
+		sf     *spyFetcher
+		events []watch.Event
+		w      *watch.Watcher
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		sb = &stubBumper{
+			result: bumper.Result{
+				Commits: []*git.Commit{
+					{Hash: "def123", StoryID: 2, StoryName: "Two", Accepted: false},
+					{Hash: "789abc", StoryID: 1, StoryName: "One", Accepted: true},
+				},
+				BumpSHA: "789abc",
+			},
+		}
+		sf = &spyFetcher{}
+		events = nil
+		w = watch.New(sb, func(e watch.Event) {
+			events = append(events, e)
+		},
+			watch.WithFetcher(sf),
+			watch.WithClock(func() time.Time { return now }),
+		)
+	})
+
+	It("fetches and emits the initial state", func() {
+		Expect(w.Check()).To(Succeed())
+
+		Expect(sf.called).To(Equal(1))
+		Expect(events).To(Equal([]watch.Event{
+			{
+				Time:        now,
+				BumpSHA:     "789abc",
+				NewCommits:  []string{"789abc"},
+				NewBlockers: []watch.Story{{ID: 2, Name: "Two"}},
+				Blockers:    []watch.Story{{ID: 2, Name: "Two"}},
+			},
+		}))
+	})
+
+	It("does not emit an event when nothing changed", func() {
+		Expect(w.Check()).To(Succeed())
+		Expect(w.Check()).To(Succeed())
+
+		Expect(events).To(HaveLen(1))
+	})
+
+	It("emits an event when a blocking story is accepted", func() {
+		Expect(w.Check()).To(Succeed())
+
+		sb.result = bumper.Result{
+			Commits: []*git.Commit{
+				{Hash: "def123", StoryID: 2, StoryName: "Two", Accepted: true},
+				{Hash: "789abc", StoryID: 1, StoryName: "One", Accepted: true},
+			},
+			BumpSHA: "def123",
+		}
+		Expect(w.Check()).To(Succeed())
+
+		Expect(events).To(HaveLen(2))
+		Expect(events[1]).To(Equal(watch.Event{
+			Time:            now,
+			BumpSHA:         "def123",
+			PreviousBumpSHA: "789abc",
+			NewCommits:      []string{"def123"},
+			Accepted:        []watch.Story{{ID: 2, Name: "Two"}},
+		}))
+	})
+
+	It("emits an event when a new story blocks", func() {
+		Expect(w.Check()).To(Succeed())
+
+		sb.result.Commits = append([]*git.Commit{
+			{Hash: "fed321", StoryID: 3, StoryName: "Three", Accepted: false},
+		}, sb.result.Commits...)
+		Expect(w.Check()).To(Succeed())
+
+		Expect(events).To(HaveLen(2))
+		Expect(events[1].NewBlockers).To(Equal([]watch.Story{{ID: 3, Name: "Three"}}))
+		Expect(events[1].Blockers).To(Equal([]watch.Story{
+			{ID: 2, Name: "Two"},
+			{ID: 3, Name: "Three"},
+		}))
+	})
+
+	It("resets the cache before each check", func() {
+		sc := &spyCache{}
+		w = watch.New(sb, func(e watch.Event) {},
+			watch.WithCache(sc),
+		)
+
+		Expect(w.Check()).To(Succeed())
+		Expect(w.Check()).To(Succeed())
+
+		Expect(sc.resets).To(Equal(2))
+	})
+
+	It("returns an error if fetching fails", func() {
+		sf.err = errors.New("could not fetch")
+
+		Expect(w.Check()).ToNot(Succeed())
+		Expect(events).To(BeEmpty())
+	})
+
+	It("returns an error if bumping fails", func() {
+		sb.err = errors.New("could not bump")
+
+		Expect(w.Check()).ToNot(Succeed())
+		Expect(events).To(BeEmpty())
+	})
+
+	It("checks until stopped", func() {
+		w = watch.New(sb, func(e watch.Event) {},
+			watch.WithFetcher(sf),
+			watch.WithInterval(time.Millisecond),
+		)
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			w.Run(stop)
+			close(done)
+		}()
+
+		Eventually(sf.calls).Should(BeNumerically(">", 1))
+		close(stop)
+		Eventually(done).Should(BeClosed())
+	})
+})
+
+type stubBumper struct {
+	result bumper.Result
+	err    error
+}
+
+func (s *stubBumper) Bump() (bumper.Result, error) {
+	return s.result, s.err
+}
+
+type spyFetcher struct {
+	mu     sync.Mutex
+	called int
+	err    error
+}
+
+func (s *spyFetcher) Fetch() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.called++
+	return s.err
+}
+
+func (s *spyFetcher) calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.called
+}
+
+type spyCache struct {
+	resets int
+}
+
+func (s *spyCache) Reset() {
+	s.resets++
+}